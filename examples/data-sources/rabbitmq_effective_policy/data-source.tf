@@ -0,0 +1,9 @@
+data "rabbitmq_effective_policy" "orders" {
+  vhost = "/"
+  name  = "orders"
+  kind  = "queue"
+}
+
+output "orders_dead_letter_exchange" {
+  value = jsondecode(data.rabbitmq_effective_policy.orders.definition_json)["dead-letter-exchange"]
+}
@@ -0,0 +1,11 @@
+data "rabbitmq_federation_links" "dc1" {
+  vhost    = rabbitmq_federation_upstream.dc1.vhost
+  upstream = rabbitmq_federation_upstream.dc1.name
+}
+
+output "failed_federation_links" {
+  value = {
+    for l in data.rabbitmq_federation_links.dc1.links :
+    "${l.type} ${l.local_object}" => l.error if l.status != "running"
+  }
+}
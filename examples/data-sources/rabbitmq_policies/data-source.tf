@@ -0,0 +1,11 @@
+data "rabbitmq_policies" "queues" {
+  vhost    = "/"
+  apply_to = "queues"
+}
+
+output "dead_letter_exchanges" {
+  value = {
+    for p in data.rabbitmq_policies.queues.policies :
+    p.name => lookup(jsondecode(p.definition_json), "dead-letter-exchange", null)
+  }
+}
@@ -7,6 +7,6 @@ import (
 
 func main() {
 	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: rabbitmq.Provider,
+		GRPCProviderFunc: rabbitmq.GRPCProviderServer,
 	})
 }
@@ -0,0 +1,162 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourcesBindings() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcesReadBindings,
+		Description: "The `rabbitmq_bindings` data source lists the bindings of a vhost.",
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The id of the data source. This is the vhost.",
+			},
+			"vhost": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "/",
+				Description: "The vhost to list the bindings of.",
+			},
+			"source": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list bindings whose source is this exchange.",
+			},
+			"destination": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list bindings whose destination is this queue or exchange.",
+			},
+			"destination_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"queue", "exchange"}, false),
+				Description:  "Only list bindings whose destination is of this type. Can be `queue` or `exchange`.",
+			},
+			"routing_key_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "A regular expression the routing key of the bindings must match.",
+			},
+			"bindings": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching bindings.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"source": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The source exchange. The default exchange is an empty string.",
+						},
+						"destination": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The destination queue or exchange.",
+						},
+						"destination_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the destination.",
+						},
+						"routing_key": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The routing key of the binding.",
+						},
+						"properties_key": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The key identifying the binding among the bindings between its source and destination.",
+						},
+						"arguments": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Description: "The arguments of the binding. Non-string values are encoded as JSON.",
+						},
+						"arguments_json": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The arguments of the binding as a JSON string, with their original types.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourcesReadBindings(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	rmqc := meta.(*providerMeta).Client
+
+	vhost := d.Get("vhost").(string)
+
+	var routingKeyRegex *regexp.Regexp
+	if v, ok := d.GetOk("routing_key_regex"); ok {
+		var err error
+		routingKeyRegex, err = regexp.Compile(v.(string))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("invalid routing_key_regex %q: %w", v, err))
+		}
+	}
+
+	bindings, err := rmqc.ListBindingsIn(vhost)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: %d bindings retrieved in vhost %s", len(bindings), vhost)
+
+	result := make([]map[string]interface{}, 0, len(bindings))
+	for _, binding := range bindings {
+		if v, ok := d.GetOk("source"); ok && binding.Source != v.(string) {
+			continue
+		}
+
+		if v, ok := d.GetOk("destination"); ok && binding.Destination != v.(string) {
+			continue
+		}
+
+		if v, ok := d.GetOk("destination_type"); ok && binding.DestinationType != v.(string) {
+			continue
+		}
+
+		if routingKeyRegex != nil && !routingKeyRegex.MatchString(binding.RoutingKey) {
+			continue
+		}
+
+		arguments, argumentsJson, err := flattenArguments(binding.Arguments)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		result = append(result, map[string]interface{}{
+			"source":           binding.Source,
+			"destination":      binding.Destination,
+			"destination_type": binding.DestinationType,
+			"routing_key":      binding.RoutingKey,
+			"properties_key":   binding.PropertiesKey,
+			"arguments":        arguments,
+			"arguments_json":   argumentsJson,
+		})
+	}
+
+	d.Set("bindings", result)
+
+	d.SetId(vhost)
+
+	return diags
+}
@@ -0,0 +1,107 @@
+package rabbitmq
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceBindings(t *testing.T) {
+	dataSourceName := "data.rabbitmq_bindings.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceBindingsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "bindings.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "bindings.0.source", "events"),
+					resource.TestCheckResourceAttr(dataSourceName, "bindings.0.destination", "orders"),
+					resource.TestCheckResourceAttr(dataSourceName, "bindings.0.destination_type", "queue"),
+					resource.TestCheckResourceAttr(dataSourceName, "bindings.0.routing_key", "orders.created"),
+					resource.TestCheckResourceAttr(dataSourceName, "bindings.0.arguments.x-priority", "5"),
+					resource.TestCheckResourceAttr(dataSourceName, "bindings.0.arguments_json", `{"x-priority":5}`),
+					resource.TestCheckResourceAttrPair(dataSourceName, "bindings.0.properties_key", "rabbitmq_binding.created", "properties_key"),
+					// The implicit binding of the default exchange is listed too.
+					resource.TestCheckResourceAttr("data.rabbitmq_bindings.to_orders", "bindings.#", "3"),
+					resource.TestCheckResourceAttr("data.rabbitmq_bindings.from_events", "bindings.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceBindingsConfig = `
+resource "rabbitmq_vhost" "test" {
+    name = "testvhost"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user  = "guest"
+    vhost = rabbitmq_vhost.test.name
+    permissions {
+        configure = ".*"
+        write     = ".*"
+        read      = ".*"
+    }
+}
+
+resource "rabbitmq_exchange" "events" {
+    name  = "events"
+    vhost = rabbitmq_permissions.guest.vhost
+    settings {
+        type    = "topic"
+        durable = true
+    }
+}
+
+resource "rabbitmq_queue" "orders" {
+    name  = "orders"
+    vhost = rabbitmq_permissions.guest.vhost
+    settings {
+        durable = true
+    }
+}
+
+resource "rabbitmq_binding" "created" {
+    source           = rabbitmq_exchange.events.name
+    vhost            = rabbitmq_permissions.guest.vhost
+    destination      = rabbitmq_queue.orders.name
+    destination_type = "queue"
+    routing_key      = "orders.created"
+    arguments_json   = jsonencode({ "x-priority" = 5 })
+}
+
+resource "rabbitmq_binding" "cancelled" {
+    source           = rabbitmq_exchange.events.name
+    vhost            = rabbitmq_permissions.guest.vhost
+    destination      = rabbitmq_queue.orders.name
+    destination_type = "queue"
+    routing_key      = "orders.cancelled"
+}
+
+data "rabbitmq_bindings" "test" {
+    vhost             = rabbitmq_vhost.test.name
+    source            = "events"
+    destination_type  = "queue"
+    routing_key_regex = "\\.created$"
+
+    depends_on = [rabbitmq_binding.created, rabbitmq_binding.cancelled]
+}
+
+data "rabbitmq_bindings" "to_orders" {
+    vhost       = rabbitmq_vhost.test.name
+    destination = "orders"
+
+    depends_on = [rabbitmq_binding.created, rabbitmq_binding.cancelled]
+}
+
+data "rabbitmq_bindings" "from_events" {
+    vhost  = rabbitmq_vhost.test.name
+    source = "events"
+
+    depends_on = [rabbitmq_binding.created, rabbitmq_binding.cancelled]
+}
+`
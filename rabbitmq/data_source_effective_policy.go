@@ -0,0 +1,218 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourcesEffectivePolicy() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcesReadEffectivePolicy,
+		Description: "The `rabbitmq_effective_policy` data source evaluates the policies and operator policies of a vhost to find the ones applying to a queue or an exchange, which does not need to exist.",
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The id of the data source. This is composed of `kind/name@vhost`.",
+			},
+			"vhost": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "/",
+				Description: "The vhost of the object.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the queue or exchange.",
+			},
+			"kind": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"queue", "exchange"}, false),
+				Description:  "The kind of object. Can be `queue` or `exchange`.",
+			},
+			"queue_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "classic",
+				ValidateFunc: validation.StringInSlice([]string{"classic", "quorum", "stream"}, false),
+				Description:  "The type of the queue, for policies applying to `classic_queues`, `quorum_queues` or `streams`. The type of the queue is used instead when it exists. Defaults to `classic`.",
+			},
+			"policy": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the policy applying to the object, or an empty string.",
+			},
+			"operator_policy": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the operator policy applying to the object, or an empty string.",
+			},
+			"definition_json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The effective definition, merging the definitions of the policy and the operator policy, as a JSON object.",
+			},
+			"shadowed_policies": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The policies and operator policies matching the object that do not apply because another one takes precedence.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the policy.",
+						},
+						"operator": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether this is an operator policy.",
+						},
+						"priority": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The priority of the policy.",
+						},
+					},
+				},
+			},
+			"exists": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the object exists. The result is then checked against the policies the server applies to it.",
+			},
+		},
+	}
+}
+
+// policyObjectInfo holds the fields of queues and exchanges about policies,
+// some of which rabbit-hole does not expose.
+type policyObjectInfo struct {
+	Type                      string                 `json:"type"`
+	Policy                    string                 `json:"policy"`
+	OperatorPolicy            string                 `json:"operator_policy"`
+	EffectivePolicyDefinition map[string]interface{} `json:"effective_policy_definition"`
+}
+
+func dataSourcesReadEffectivePolicy(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	rmqc := meta.(*providerMeta).Client
+
+	vhost := d.Get("vhost").(string)
+	name := d.Get("name").(string)
+	kind := d.Get("kind").(string)
+	queueType := d.Get("queue_type").(string)
+
+	var object policyObjectInfo
+	exists := true
+	path := fmt.Sprintf("%ss/%s/%s", kind, url.PathEscape(vhost), url.PathEscape(name))
+	if _, err := managementRequest(rmqc, http.MethodGet, path, nil, &object); err != nil {
+		if !isNotFound(err) {
+			return diag.FromErr(err)
+		}
+		exists = false
+	}
+
+	if exists && kind == "queue" && object.Type != "" {
+		queueType = object.Type
+	}
+
+	candidates, err := listPolicyCandidates(rmqc, vhost)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	effective := resolveEffectivePolicy(candidates, name, policyTarget(kind, queueType))
+
+	log.Printf("[DEBUG] RabbitMQ: Effective policy of %s %s@%s: %#v", kind, name, vhost, effective)
+
+	if len(effective.Unevaluated) > 0 {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Some policies could not be evaluated",
+			Detail:   fmt.Sprintf("The patterns of %s use regular expression syntax that is only supported by RabbitMQ, these policies were ignored.", strings.Join(policyNames(effective.Unevaluated), ", ")),
+		})
+	}
+
+	policyName := ""
+	if effective.Policy != nil {
+		policyName = effective.Policy.Name
+	}
+	operatorPolicyName := ""
+	if effective.OperatorPolicy != nil {
+		operatorPolicyName = effective.OperatorPolicy.Name
+	}
+
+	definitionJson, err := json.Marshal(effective.Definition)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if exists {
+		diags = append(diags, checkEffectivePolicy(kind, name, object, policyName, operatorPolicyName, definitionJson)...)
+	}
+
+	shadowed := make([]map[string]interface{}, 0, len(effective.Shadowed))
+	for _, p := range effective.Shadowed {
+		shadowed = append(shadowed, map[string]interface{}{
+			"name":     p.Name,
+			"operator": p.Operator,
+			"priority": p.Priority,
+		})
+	}
+
+	d.Set("policy", policyName)
+	d.Set("operator_policy", operatorPolicyName)
+	d.Set("definition_json", string(definitionJson))
+	d.Set("shadowed_policies", shadowed)
+	d.Set("exists", exists)
+
+	d.SetId(fmt.Sprintf("%s/%s@%s", kind, name, vhost))
+
+	return diags
+}
+
+// checkEffectivePolicy warns when the policies the server applies to an
+// existing object are not the ones the data source found.
+func checkEffectivePolicy(kind string, name string, object policyObjectInfo, policyName string, operatorPolicyName string, definitionJson []byte) diag.Diagnostics {
+	var differences []string
+
+	if object.Policy != policyName {
+		differences = append(differences, fmt.Sprintf("the server applies the policy %q, not %q", object.Policy, policyName))
+	}
+
+	if kind == "queue" {
+		if object.OperatorPolicy != operatorPolicyName {
+			differences = append(differences, fmt.Sprintf("the server applies the operator policy %q, not %q", object.OperatorPolicy, operatorPolicyName))
+		}
+
+		var definition map[string]interface{}
+		if err := json.Unmarshal(definitionJson, &definition); err == nil && len(object.EffectivePolicyDefinition)+len(definition) > 0 && !reflect.DeepEqual(definition, object.EffectivePolicyDefinition) {
+			serverJson, _ := json.Marshal(object.EffectivePolicyDefinition)
+			differences = append(differences, fmt.Sprintf("the effective definition on the server is %s, not %s", serverJson, definitionJson))
+		}
+	}
+
+	if len(differences) == 0 {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("The effective policy of %s %s differs on the server", kind, name),
+		Detail:   fmt.Sprintf("For the %s %s, %s. The server may not have applied the latest policies yet, or evaluates a pattern differently.", kind, name, strings.Join(differences, ", ")),
+	}}
+}
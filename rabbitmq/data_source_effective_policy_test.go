@@ -0,0 +1,125 @@
+package rabbitmq
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceEffectivePolicy(t *testing.T) {
+	dataSourceName := "data.rabbitmq_effective_policy.orders"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceEffectivePolicyConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "exists", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "policy", "orders-dlx"),
+					resource.TestCheckResourceAttr(dataSourceName, "operator_policy", "limits"),
+					resource.TestCheckResourceAttr(dataSourceName, "definition_json", `{"dead-letter-exchange":"dlx","max-length":1000}`),
+					resource.TestCheckResourceAttr(dataSourceName, "shadowed_policies.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "shadowed_policies.0.name", "all"),
+					resource.TestCheckResourceAttr(dataSourceName, "shadowed_policies.0.operator", "false"),
+					resource.TestCheckResourceAttr(dataSourceName, "shadowed_policies.0.priority", "0"),
+					// Policies apply to objects that do not exist yet.
+					resource.TestCheckResourceAttr("data.rabbitmq_effective_policy.invoices", "exists", "false"),
+					resource.TestCheckResourceAttr("data.rabbitmq_effective_policy.invoices", "policy", "all"),
+					resource.TestCheckResourceAttr("data.rabbitmq_effective_policy.invoices", "shadowed_policies.#", "0"),
+					// Operator policies do not apply to exchanges.
+					resource.TestCheckResourceAttr("data.rabbitmq_effective_policy.events", "policy", "all"),
+					resource.TestCheckResourceAttr("data.rabbitmq_effective_policy.events", "operator_policy", ""),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceEffectivePolicyConfig = `
+resource "rabbitmq_vhost" "test" {
+    name = "testvhost"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user  = "guest"
+    vhost = rabbitmq_vhost.test.name
+    permissions {
+        configure = ".*"
+        write     = ".*"
+        read      = ".*"
+    }
+}
+
+resource "rabbitmq_policy" "all" {
+    name  = "all"
+    vhost = rabbitmq_permissions.guest.vhost
+    policy {
+        pattern  = ".*"
+        priority = 0
+        apply_to = "all"
+        definition = {
+            alternate-exchange = "unrouted"
+        }
+    }
+}
+
+resource "rabbitmq_policy" "orders_dlx" {
+    name  = "orders-dlx"
+    vhost = rabbitmq_permissions.guest.vhost
+    policy {
+        pattern  = "^orders$"
+        priority = 10
+        apply_to = "queues"
+        definition = {
+            dead-letter-exchange = "dlx"
+            max-length           = 5000
+        }
+    }
+}
+
+resource "rabbitmq_operator_policy" "limits" {
+    name  = "limits"
+    vhost = rabbitmq_permissions.guest.vhost
+    policy {
+        pattern  = ".*"
+        priority = 0
+        apply_to = "queues"
+        definition = {
+            max-length = 1000
+        }
+    }
+}
+
+resource "rabbitmq_queue" "orders" {
+    name  = "orders"
+    vhost = rabbitmq_permissions.guest.vhost
+    settings {
+        durable = true
+    }
+}
+
+data "rabbitmq_effective_policy" "orders" {
+    vhost = rabbitmq_queue.orders.vhost
+    name  = rabbitmq_queue.orders.name
+    kind  = "queue"
+
+    depends_on = [rabbitmq_policy.all, rabbitmq_policy.orders_dlx, rabbitmq_operator_policy.limits]
+}
+
+data "rabbitmq_effective_policy" "invoices" {
+    vhost = rabbitmq_permissions.guest.vhost
+    name  = "invoices"
+    kind  = "queue"
+
+    depends_on = [rabbitmq_policy.all, rabbitmq_policy.orders_dlx, rabbitmq_operator_policy.limits]
+}
+
+data "rabbitmq_effective_policy" "events" {
+    vhost = rabbitmq_permissions.guest.vhost
+    name  = "events"
+    kind  = "exchange"
+
+    depends_on = [rabbitmq_policy.all, rabbitmq_policy.orders_dlx, rabbitmq_operator_policy.limits]
+}`
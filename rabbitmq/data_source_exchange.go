@@ -57,10 +57,28 @@ func dataSourcesExchange() *schema.Resource {
 							Description: "Whether the exchange is auto-deleted when no longer in use.",
 						},
 
+						"internal": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the exchange is internal.",
+						},
+
+						"alternate_exchange": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The exchange to which messages that can't be routed are republished.",
+						},
+
 						"arguments": {
 							Type:        schema.TypeMap,
 							Optional:    true,
-							Description: "Additional key/value settings for the exchange.",
+							Description: "Additional key/value settings for the exchange. Non-string values are encoded as JSON.",
+						},
+
+						"arguments_json": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The arguments of the exchange as a JSON string, with their original types.",
 						},
 					},
 				},
@@ -72,7 +90,7 @@ func dataSourcesExchange() *schema.Resource {
 func dataSourcesReadExchange(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name := d.Get("name").(string)
 	vhost := d.Get("vhost").(string)
@@ -88,16 +106,42 @@ func dataSourcesReadExchange(ctx context.Context, d *schema.ResourceData, meta i
 	d.Set("name", exchangeSettings.Name)
 	d.Set("vhost", exchangeSettings.Vhost)
 
-	exchange := make([]map[string]interface{}, 1)
-	e := make(map[string]interface{})
-	e["type"] = exchangeSettings.Type
-	e["durable"] = exchangeSettings.Durable
-	e["auto_delete"] = exchangeSettings.AutoDelete
-	e["arguments"] = exchangeSettings.Arguments
-	exchange[0] = e
+	e, err := flattenExchangeSettings(rabbithole.ExchangeInfo{
+		Type:       exchangeSettings.Type,
+		Durable:    exchangeSettings.Durable,
+		AutoDelete: rabbithole.AutoDelete(exchangeSettings.AutoDelete),
+		Internal:   exchangeSettings.Internal,
+		Arguments:  exchangeSettings.Arguments,
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	exchange := []map[string]interface{}{e}
 	d.Set("settings", exchange)
 
 	d.SetId(id)
 
 	return diags
 }
+
+// flattenExchangeSettings returns the settings of an exchange as read by the data sources.
+func flattenExchangeSettings(exchange rabbithole.ExchangeInfo) (map[string]interface{}, error) {
+	arguments, argumentsJson, err := flattenArguments(exchange.Arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	e := map[string]interface{}{
+		"type":           exchange.Type,
+		"durable":        exchange.Durable,
+		"auto_delete":    bool(exchange.AutoDelete),
+		"internal":       exchange.Internal,
+		"arguments":      arguments,
+		"arguments_json": argumentsJson,
+	}
+	if v, ok := exchange.Arguments["alternate-exchange"].(string); ok {
+		e["alternate_exchange"] = v
+	}
+
+	return e, nil
+}
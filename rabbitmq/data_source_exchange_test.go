@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"testing"
 
-	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -28,6 +26,8 @@ func TestAccDataSourceExchange(t *testing.T) {
 					resource.TestCheckResourceAttr(dataSourceName, "settings.0.type", "fanout"),
 					resource.TestCheckResourceAttr(dataSourceName, "settings.0.durable", "true"),
 					resource.TestCheckResourceAttr(dataSourceName, "settings.0.auto_delete", "false"),
+					resource.TestCheckResourceAttr(dataSourceName, "settings.0.arguments_json", "{}"),
+					resource.TestCheckResourceAttr(dataSourceName, "settings.0.internal", "false"),
 				),
 			},
 		},
@@ -45,7 +45,7 @@ func testAccCheckDataSourceExchangeExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("data source ID is not set")
 		}
 
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		name := rs.Primary.Attributes["name"]
 		vhost := rs.Primary.Attributes["vhost"]
 
@@ -73,7 +73,7 @@ func testAccCheckExchangeExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("exchange id not set")
 		}
 
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		name := rs.Primary.Attributes["name"]
 		vhost := rs.Primary.Attributes["vhost"]
 
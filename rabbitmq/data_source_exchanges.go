@@ -0,0 +1,187 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourcesExchanges() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcesReadExchanges,
+		Description: "The `rabbitmq_exchanges` data source lists the exchanges of a vhost.",
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The id of the data source. This is the vhost.",
+			},
+			"vhost": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "/",
+				Description: "The vhost to list the exchanges of.",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "A regular expression the name of the exchanges must match.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list exchanges of this type.",
+			},
+			"durable": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only list durable exchanges when `true`, or transient exchanges when `false`. All exchanges are listed when unset.",
+			},
+			"include_default_exchanges": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to list the default exchange and the `amq.*` exchanges declared by the server.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the matching exchanges.",
+			},
+			"exchanges": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching exchanges.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the exchange.",
+						},
+						"vhost": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The vhost of the exchange.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the exchange.",
+						},
+						"durable": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the exchange is durable or not.",
+						},
+						"auto_delete": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the exchange is auto-deleted when no longer in use.",
+						},
+						"internal": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the exchange is internal.",
+						},
+						"alternate_exchange": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The exchange to which messages that can't be routed are republished.",
+						},
+						"arguments": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Description: "The arguments of the exchange. Non-string values are encoded as JSON.",
+						},
+						"arguments_json": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The arguments of the exchange as a JSON string, with their original types.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourcesReadExchanges(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	rmqc := meta.(*providerMeta).Client
+
+	vhost := d.Get("vhost").(string)
+	exchangeType := d.Get("type").(string)
+	includeDefaults := d.Get("include_default_exchanges").(bool)
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		var err error
+		nameRegex, err = regexp.Compile(v.(string))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("invalid name_regex %q: %w", v, err))
+		}
+	}
+
+	// `durable` is only a filter when it is set in the configuration.
+	var durable *bool
+	if config := d.GetRawConfig(); !config.IsNull() && config.IsKnown() {
+		if v := config.GetAttr("durable"); !v.IsNull() && v.IsKnown() {
+			value := v.True()
+			durable = &value
+		}
+	}
+
+	exchanges, err := rmqc.ListExchangesIn(vhost)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: %d exchanges retrieved in vhost %s", len(exchanges), vhost)
+
+	names := make([]string, 0, len(exchanges))
+	result := make([]map[string]interface{}, 0, len(exchanges))
+	for _, exchange := range exchanges {
+		if !includeDefaults && (exchange.Name == "" || strings.HasPrefix(exchange.Name, "amq.")) {
+			continue
+		}
+
+		if nameRegex != nil && !nameRegex.MatchString(exchange.Name) {
+			continue
+		}
+
+		if exchangeType != "" && exchange.Type != exchangeType {
+			continue
+		}
+
+		if durable != nil && exchange.Durable != *durable {
+			continue
+		}
+
+		e, err := flattenExchangeSettings(exchange)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		e["name"] = exchange.Name
+		e["vhost"] = exchange.Vhost
+
+		names = append(names, exchange.Name)
+		result = append(result, e)
+	}
+
+	d.Set("names", names)
+	d.Set("exchanges", result)
+
+	d.SetId(vhost)
+
+	return diags
+}
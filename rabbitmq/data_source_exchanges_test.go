@@ -0,0 +1,107 @@
+package rabbitmq
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceExchanges(t *testing.T) {
+	dataSourceName := "data.rabbitmq_exchanges.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceExchangesConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "names.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "names.0", "events.orders"),
+					resource.TestCheckResourceAttr(dataSourceName, "exchanges.0.type", "topic"),
+					resource.TestCheckResourceAttr(dataSourceName, "exchanges.0.vhost", "testvhost"),
+					resource.TestCheckResourceAttr(dataSourceName, "exchanges.0.durable", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "exchanges.0.alternate_exchange", "events.unroutable"),
+					resource.TestCheckResourceAttr("data.rabbitmq_exchanges.all", "names.#", "3"),
+					resource.TestCheckResourceAttr("data.rabbitmq_exchanges.transient", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.rabbitmq_exchanges.transient", "names.0", "events.billing"),
+					resource.TestCheckResourceAttrSet("data.rabbitmq_exchanges.defaults", "exchanges.0.name"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceExchangesConfig = `
+resource "rabbitmq_vhost" "test" {
+    name = "testvhost"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user  = "guest"
+    vhost = rabbitmq_vhost.test.name
+    permissions {
+        configure = ".*"
+        write     = ".*"
+        read      = ".*"
+    }
+}
+
+resource "rabbitmq_exchange" "unroutable" {
+    name  = "events.unroutable"
+    vhost = rabbitmq_permissions.guest.vhost
+    settings {
+        type    = "fanout"
+        durable = true
+    }
+}
+
+resource "rabbitmq_exchange" "orders" {
+    name  = "events.orders"
+    vhost = rabbitmq_permissions.guest.vhost
+    settings {
+        type               = "topic"
+        durable            = true
+        alternate_exchange = rabbitmq_exchange.unroutable.name
+    }
+}
+
+resource "rabbitmq_exchange" "billing" {
+    name  = "events.billing"
+    vhost = rabbitmq_permissions.guest.vhost
+    settings {
+        type    = "topic"
+        durable = false
+    }
+}
+
+data "rabbitmq_exchanges" "test" {
+    vhost      = rabbitmq_vhost.test.name
+    name_regex = "^events\\."
+    type       = "topic"
+    durable    = true
+
+    depends_on = [rabbitmq_exchange.unroutable, rabbitmq_exchange.orders, rabbitmq_exchange.billing]
+}
+
+data "rabbitmq_exchanges" "transient" {
+    vhost   = rabbitmq_vhost.test.name
+    durable = false
+
+    depends_on = [rabbitmq_exchange.unroutable, rabbitmq_exchange.orders, rabbitmq_exchange.billing]
+}
+
+data "rabbitmq_exchanges" "all" {
+    vhost = rabbitmq_vhost.test.name
+
+    depends_on = [rabbitmq_exchange.unroutable, rabbitmq_exchange.orders, rabbitmq_exchange.billing]
+}
+
+data "rabbitmq_exchanges" "defaults" {
+    vhost                     = rabbitmq_vhost.test.name
+    name_regex                = "^amq\\."
+    include_default_exchanges = true
+
+    depends_on = [rabbitmq_permissions.guest]
+}
+`
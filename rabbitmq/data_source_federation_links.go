@@ -0,0 +1,131 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcesFederationLinks() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcesReadFederationLinks,
+		Description: "The `rabbitmq_federation_links` data source reports the status of the federation links of a vhost or an upstream. Links are started by the policies federating exchanges or queues from an upstream, so an upstream has no links until a policy uses it.",
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The id of the data source. This is `upstream@vhost`, with `*` for an unset vhost or upstream.",
+			},
+			"vhost": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The vhost to list the links of. The links of all vhosts are listed when unset.",
+			},
+			"upstream": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list the links of this federation upstream.",
+			},
+			"all_running": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether all the listed links are running. This is `true` when there are no links.",
+			},
+			"links": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching links, sorted by vhost, upstream, type and local object.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"upstream": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the federation upstream.",
+						},
+						"vhost": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The vhost of the link.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the federated object, `exchange` or `queue`.",
+						},
+						"local_object": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the federated exchange or queue.",
+						},
+						"upstream_object": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the exchange or queue on the upstream.",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The status of the link, e.g. `starting`, `running` or `error`.",
+						},
+						"error": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The error of the link, when its status is `error`.",
+						},
+						"node": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The node running the link.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourcesReadFederationLinks(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	rmqc := meta.(*providerMeta).Client
+
+	vhost := d.Get("vhost").(string)
+	upstream := d.Get("upstream").(string)
+
+	links, err := listFederationLinks(rmqc, vhost, upstream)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: %d federation links retrieved", len(links))
+
+	result := make([]map[string]interface{}, 0, len(links))
+	for _, link := range links {
+		result = append(result, map[string]interface{}{
+			"upstream":        link.Upstream,
+			"vhost":           link.Vhost,
+			"type":            link.Type,
+			"local_object":    link.LocalObject,
+			"upstream_object": link.UpstreamObject,
+			"status":          link.Status,
+			"error":           link.Error,
+			"node":            link.Node,
+		})
+	}
+
+	d.Set("links", result)
+	d.Set("all_running", len(federationLinksNotRunning(links)) == 0)
+
+	if vhost == "" {
+		vhost = "*"
+	}
+	if upstream == "" {
+		upstream = "*"
+	}
+	d.SetId(fmt.Sprintf("%s@%s", upstream, vhost))
+
+	return diags
+}
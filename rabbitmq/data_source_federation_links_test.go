@@ -0,0 +1,97 @@
+package rabbitmq
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceFederationLinks(t *testing.T) {
+	dataSourceName := "data.rabbitmq_federation_links.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceFederationLinksConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", "local@test"),
+					resource.TestCheckResourceAttr(dataSourceName, "all_running", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "links.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "links.0.upstream", "local"),
+					resource.TestCheckResourceAttr(dataSourceName, "links.0.vhost", "test"),
+					resource.TestCheckResourceAttr(dataSourceName, "links.0.type", "exchange"),
+					resource.TestCheckResourceAttr(dataSourceName, "links.0.local_object", "federated"),
+					resource.TestCheckResourceAttr(dataSourceName, "links.0.upstream_object", "amq.topic"),
+					resource.TestCheckResourceAttr(dataSourceName, "links.0.status", "running"),
+					resource.TestCheckResourceAttr(dataSourceName, "links.0.error", ""),
+					resource.TestCheckResourceAttrSet(dataSourceName, "links.0.node"),
+					resource.TestCheckResourceAttr("data.rabbitmq_federation_links.missing", "links.#", "0"),
+					resource.TestCheckResourceAttr("data.rabbitmq_federation_links.missing", "all_running", "true"),
+				),
+			},
+		},
+	})
+}
+
+// The upstream is the default vhost of the broker itself, and is created
+// after the policy using it so that it waits for its link.
+const testAccDataSourceFederationLinksConfig = `
+resource "rabbitmq_vhost" "test" {
+    name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user  = "guest"
+    vhost = rabbitmq_vhost.test.name
+    permissions {
+        configure = ".*"
+        write     = ".*"
+        read      = ".*"
+    }
+}
+
+resource "rabbitmq_exchange" "federated" {
+    name  = "federated"
+    vhost = rabbitmq_permissions.guest.vhost
+    settings {
+        type = "topic"
+    }
+}
+
+resource "rabbitmq_policy" "federated" {
+    name  = "federated"
+    vhost = rabbitmq_permissions.guest.vhost
+    policy {
+        pattern  = "^${rabbitmq_exchange.federated.name}$"
+        priority = 0
+        apply_to = "exchanges"
+        definition = {
+            federation-upstream = "local"
+        }
+    }
+}
+
+resource "rabbitmq_federation_upstream" "local" {
+    name  = "local"
+    vhost = rabbitmq_policy.federated.vhost
+
+    wait_for_links_running = true
+
+    definition {
+        uri      = "amqp://"
+        exchange = "amq.topic"
+    }
+}
+
+data "rabbitmq_federation_links" "test" {
+    vhost    = rabbitmq_federation_upstream.local.vhost
+    upstream = rabbitmq_federation_upstream.local.name
+}
+
+data "rabbitmq_federation_links" "missing" {
+    vhost    = rabbitmq_federation_upstream.local.vhost
+    upstream = "missing"
+}
+`
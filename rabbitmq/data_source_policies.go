@@ -0,0 +1,192 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourcesPolicies() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcesReadPolicies,
+		Description: "The `rabbitmq_policies` data source lists the policies and operator policies of a vhost, or of all vhosts, including the ones not managed by Terraform.",
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The id of the data source. This is the vhost, or `*` for all vhosts.",
+			},
+			"vhost": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The vhost to list the policies of. The policies of all vhosts are listed when unset.",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "A regular expression the name of the policies must match.",
+			},
+			"apply_to": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(policyApplyToValues, false),
+				Description:  "Only list policies applying to these objects. Can be `all`, `queues`, `exchanges`, `classic_queues`, `quorum_queues` or `streams`.",
+			},
+			"policies": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching policies and operator policies, sorted by vhost, kind and name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the policy.",
+						},
+						"vhost": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The vhost of the policy.",
+						},
+						"operator": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether this is an operator policy.",
+						},
+						"pattern": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The pattern matching the names of the queues or exchanges.",
+						},
+						"apply_to": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The objects the policy applies to.",
+						},
+						"priority": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The priority of the policy.",
+						},
+						"definition_json": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The definition of the policy as a JSON object, with its original types.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourcesReadPolicies(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	rmqc := meta.(*providerMeta).Client
+
+	vhost := d.Get("vhost").(string)
+	applyTo := d.Get("apply_to").(string)
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		var err error
+		nameRegex, err = regexp.Compile(v.(string))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("invalid name_regex %q: %w", v, err))
+		}
+	}
+
+	var policies []rabbithole.Policy
+	var operatorPolicies []rabbithole.OperatorPolicy
+	var err error
+	if vhost != "" {
+		policies, err = rmqc.ListPoliciesIn(vhost)
+		if err == nil {
+			operatorPolicies, err = rmqc.ListOperatorPoliciesIn(vhost)
+		}
+	} else {
+		policies, err = rmqc.ListPolicies()
+		if err == nil {
+			operatorPolicies, err = rmqc.ListOperatorPolicies()
+		}
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: %d policies and %d operator policies retrieved", len(policies), len(operatorPolicies))
+
+	type listedPolicy struct {
+		vhost     string
+		candidate policyCandidate
+	}
+	listed := make([]listedPolicy, 0, len(policies)+len(operatorPolicies))
+	for _, p := range policies {
+		listed = append(listed, listedPolicy{p.Vhost, policyCandidate{Name: p.Name, Pattern: p.Pattern, ApplyTo: p.ApplyTo, Priority: p.Priority, Definition: p.Definition}})
+	}
+	for _, p := range operatorPolicies {
+		listed = append(listed, listedPolicy{p.Vhost, policyCandidate{Name: p.Name, Operator: true, Pattern: p.Pattern, ApplyTo: p.ApplyTo, Priority: p.Priority, Definition: p.Definition}})
+	}
+
+	sort.SliceStable(listed, func(i, j int) bool {
+		if listed[i].vhost != listed[j].vhost {
+			return listed[i].vhost < listed[j].vhost
+		}
+		if listed[i].candidate.Operator != listed[j].candidate.Operator {
+			return !listed[i].candidate.Operator
+		}
+		return listed[i].candidate.Name < listed[j].candidate.Name
+	})
+
+	result := make([]map[string]interface{}, 0, len(listed))
+	for _, l := range listed {
+		p := l.candidate
+		if nameRegex != nil && !nameRegex.MatchString(p.Name) {
+			continue
+		}
+
+		if applyTo != "" && p.ApplyTo != applyTo {
+			continue
+		}
+
+		definition := p.Definition
+		if definition == nil {
+			definition = map[string]interface{}{}
+		}
+		definitionJson, err := json.Marshal(definition)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		result = append(result, map[string]interface{}{
+			"name":            p.Name,
+			"vhost":           l.vhost,
+			"operator":        p.Operator,
+			"pattern":         p.Pattern,
+			"apply_to":        p.ApplyTo,
+			"priority":        p.Priority,
+			"definition_json": string(definitionJson),
+		})
+	}
+
+	d.Set("policies", result)
+
+	if vhost != "" {
+		d.SetId(vhost)
+	} else {
+		d.SetId("*")
+	}
+
+	return diags
+}
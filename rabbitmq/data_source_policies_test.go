@@ -0,0 +1,120 @@
+package rabbitmq
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourcePolicies(t *testing.T) {
+	dataSourceName := "data.rabbitmq_policies.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourcePoliciesConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", "testvhost"),
+					resource.TestCheckResourceAttr(dataSourceName, "policies.#", "3"),
+					resource.TestCheckResourceAttr(dataSourceName, "policies.0.name", "orders-dlx"),
+					resource.TestCheckResourceAttr(dataSourceName, "policies.0.vhost", "testvhost"),
+					resource.TestCheckResourceAttr(dataSourceName, "policies.0.operator", "false"),
+					resource.TestCheckResourceAttr(dataSourceName, "policies.0.pattern", "^orders$"),
+					resource.TestCheckResourceAttr(dataSourceName, "policies.0.apply_to", "queues"),
+					resource.TestCheckResourceAttr(dataSourceName, "policies.0.priority", "10"),
+					resource.TestCheckResourceAttr(dataSourceName, "policies.0.definition_json", `{"dead-letter-exchange":"dlx","max-length":5000}`),
+					resource.TestCheckResourceAttr(dataSourceName, "policies.1.name", "replicated"),
+					resource.TestCheckResourceAttr(dataSourceName, "policies.1.definition_json", `{"ha-mode":"nodes","ha-params":["rabbit@a","rabbit@b"]}`),
+					resource.TestCheckResourceAttr(dataSourceName, "policies.2.name", "limits"),
+					resource.TestCheckResourceAttr(dataSourceName, "policies.2.operator", "true"),
+					resource.TestCheckResourceAttr("data.rabbitmq_policies.orders", "policies.#", "1"),
+					resource.TestCheckResourceAttr("data.rabbitmq_policies.orders", "policies.0.name", "orders-dlx"),
+					resource.TestCheckResourceAttr("data.rabbitmq_policies.exchanges", "policies.#", "0"),
+					resource.TestCheckResourceAttr("data.rabbitmq_policies.all_vhosts", "id", "*"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourcePoliciesConfig = `
+resource "rabbitmq_vhost" "test" {
+    name = "testvhost"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user  = "guest"
+    vhost = rabbitmq_vhost.test.name
+    permissions {
+        configure = ".*"
+        write     = ".*"
+        read      = ".*"
+    }
+}
+
+resource "rabbitmq_policy" "orders_dlx" {
+    name  = "orders-dlx"
+    vhost = rabbitmq_permissions.guest.vhost
+    policy {
+        pattern  = "^orders$"
+        priority = 10
+        apply_to = "queues"
+        definition = {
+            dead-letter-exchange = "dlx"
+            max-length           = 5000
+        }
+    }
+}
+
+resource "rabbitmq_policy" "replicated" {
+    name  = "replicated"
+    vhost = rabbitmq_permissions.guest.vhost
+    policy {
+        pattern  = "^replicated\\."
+        priority = 0
+        apply_to = "queues"
+        definition_json = jsonencode({
+            "ha-mode"   = "nodes"
+            "ha-params" = ["rabbit@a", "rabbit@b"]
+        })
+    }
+}
+
+resource "rabbitmq_operator_policy" "limits" {
+    name  = "limits"
+    vhost = rabbitmq_permissions.guest.vhost
+    policy {
+        pattern  = ".*"
+        priority = 0
+        apply_to = "queues"
+        definition = {
+            max-length = 1000
+        }
+    }
+}
+
+data "rabbitmq_policies" "test" {
+    vhost = rabbitmq_permissions.guest.vhost
+
+    depends_on = [rabbitmq_policy.orders_dlx, rabbitmq_policy.replicated, rabbitmq_operator_policy.limits]
+}
+
+data "rabbitmq_policies" "orders" {
+    vhost      = rabbitmq_permissions.guest.vhost
+    name_regex = "^orders"
+
+    depends_on = [rabbitmq_policy.orders_dlx, rabbitmq_policy.replicated, rabbitmq_operator_policy.limits]
+}
+
+data "rabbitmq_policies" "exchanges" {
+    vhost    = rabbitmq_permissions.guest.vhost
+    apply_to = "exchanges"
+
+    depends_on = [rabbitmq_policy.orders_dlx, rabbitmq_policy.replicated, rabbitmq_operator_policy.limits]
+}
+
+data "rabbitmq_policies" "all_vhosts" {
+    depends_on = [rabbitmq_policy.orders_dlx, rabbitmq_policy.replicated, rabbitmq_operator_policy.limits]
+}`
@@ -0,0 +1,169 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcesQueue() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcesReadQueue,
+		Description: "The `rabbitmq_queue` data source retrieves information about a queue.",
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The id of the queue. This is a combination of the name and vhost.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the queue.",
+			},
+			"vhost": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "/",
+				Description: "The vhost of the queue.",
+			},
+			"settings": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"durable": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the queue survives server restarts.",
+						},
+
+						"auto_delete": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the queue is deleted when the number of consumers drops to zero.",
+						},
+
+						"exclusive": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the queue is used by only one connection and deleted when that connection closes.",
+						},
+
+						"arguments": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Description: "The arguments of the queue. Non-string values are encoded as JSON.",
+						},
+
+						"arguments_json": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The arguments of the queue as a JSON string, with their original types.",
+						},
+					},
+				},
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the queue, e.g. `classic`, `quorum` or `stream`.",
+			},
+			"node": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The node hosting the queue, or its leader replica.",
+			},
+			"leader": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The node hosting the leader replica of a quorum queue or stream.",
+			},
+			"members": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The nodes hosting a replica of a quorum queue or stream.",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the queue, e.g. `running`.",
+			},
+			"messages": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of messages in the queue.",
+			},
+			"consumers": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of consumers of the queue.",
+			},
+			"effective_policy": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the policy applied to the queue, if any.",
+			},
+		},
+	}
+}
+
+func dataSourcesReadQueue(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	rmqc := meta.(*providerMeta).Client
+
+	name := d.Get("name").(string)
+	vhost := d.Get("vhost").(string)
+	id := fmt.Sprintf("%s@%s", name, vhost)
+
+	queueSettings, err := rmqc.GetQueue(vhost, name)
+	if err != nil {
+		return diag.FromErr(checkDeleted(d, err))
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: Queue retrieved %s: %#v", id, queueSettings)
+
+	settings, err := flattenQueueSettings(rabbithole.QueueInfo(*queueSettings))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("name", queueSettings.Name)
+	d.Set("vhost", queueSettings.Vhost)
+	d.Set("settings", []map[string]interface{}{settings})
+	d.Set("type", queueSettings.Type)
+	d.Set("node", queueSettings.Node)
+	d.Set("leader", queueSettings.Leader)
+	d.Set("members", queueSettings.Members)
+	d.Set("state", queueSettings.Status)
+	d.Set("messages", queueSettings.Messages)
+	d.Set("consumers", queueSettings.Consumers)
+	d.Set("effective_policy", queueSettings.Policy)
+
+	d.SetId(id)
+
+	return diags
+}
+
+// flattenQueueSettings returns the settings of a queue as read by the data sources.
+func flattenQueueSettings(queue rabbithole.QueueInfo) (map[string]interface{}, error) {
+	arguments, argumentsJson, err := flattenArguments(queue.Arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"durable":        queue.Durable,
+		"auto_delete":    bool(queue.AutoDelete),
+		"exclusive":      queue.Exclusive,
+		"arguments":      arguments,
+		"arguments_json": argumentsJson,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package rabbitmq
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceQueue(t *testing.T) {
+	dataSourceName := "data.rabbitmq_queue.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceQueueConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", "test@testvhost"),
+					resource.TestCheckResourceAttr(dataSourceName, "name", "test"),
+					resource.TestCheckResourceAttr(dataSourceName, "vhost", "testvhost"),
+					resource.TestCheckResourceAttr(dataSourceName, "type", "quorum"),
+					resource.TestCheckResourceAttr(dataSourceName, "settings.0.durable", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "settings.0.auto_delete", "false"),
+					resource.TestCheckResourceAttr(dataSourceName, "settings.0.arguments.x-queue-type", "quorum"),
+					resource.TestCheckResourceAttr(dataSourceName, "settings.0.arguments.x-max-length", "100"),
+					resource.TestCheckResourceAttr(dataSourceName, "settings.0.arguments_json", `{"x-max-length":100,"x-queue-type":"quorum"}`),
+					resource.TestCheckResourceAttr(dataSourceName, "messages", "0"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "leader"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceQueueConfig = `
+resource "rabbitmq_vhost" "test" {
+    name = "testvhost"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user  = "guest"
+    vhost = rabbitmq_vhost.test.name
+    permissions {
+        configure = ".*"
+        write     = ".*"
+        read      = ".*"
+    }
+}
+
+resource "rabbitmq_queue" "test" {
+    name  = "test"
+    vhost = rabbitmq_permissions.guest.vhost
+    settings {
+        durable    = true
+        max_length = 100
+        arguments = {
+            "x-queue-type" = "quorum"
+        }
+    }
+}
+
+data "rabbitmq_queue" "test" {
+    name  = rabbitmq_queue.test.name
+    vhost = rabbitmq_queue.test.vhost
+}
+`
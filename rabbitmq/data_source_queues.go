@@ -0,0 +1,184 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourcesQueues() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcesReadQueues,
+		Description: "The `rabbitmq_queues` data source lists the queues of a vhost.",
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The id of the data source. This is the vhost.",
+			},
+			"vhost": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "/",
+				Description: "The vhost to list the queues of.",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "A regular expression the name of the queues must match.",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"classic", "quorum", "stream"}, false),
+				Description:  "Only list queues of this type. Can be `classic`, `quorum` or `stream`.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the matching queues.",
+			},
+			"queues": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching queues.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the queue.",
+						},
+						"vhost": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The vhost of the queue.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the queue.",
+						},
+						"durable": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the queue survives server restarts.",
+						},
+						"auto_delete": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the queue is deleted when the number of consumers drops to zero.",
+						},
+						"exclusive": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the queue is used by only one connection and deleted when that connection closes.",
+						},
+						"arguments": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Description: "The arguments of the queue. Non-string values are encoded as JSON.",
+						},
+						"arguments_json": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The arguments of the queue as a JSON string, with their original types.",
+						},
+						"node": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The node hosting the queue, or its leader replica.",
+						},
+						"state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The state of the queue.",
+						},
+						"messages": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of messages in the queue.",
+						},
+						"consumers": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of consumers of the queue.",
+						},
+						"effective_policy": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the policy applied to the queue, if any.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourcesReadQueues(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	rmqc := meta.(*providerMeta).Client
+
+	vhost := d.Get("vhost").(string)
+	queueType := d.Get("type").(string)
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		var err error
+		nameRegex, err = regexp.Compile(v.(string))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("invalid name_regex %q: %w", v, err))
+		}
+	}
+
+	queues, err := rmqc.ListQueuesIn(vhost)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: %d queues retrieved in vhost %s", len(queues), vhost)
+
+	names := make([]string, 0, len(queues))
+	result := make([]map[string]interface{}, 0, len(queues))
+	for _, queue := range queues {
+		if nameRegex != nil && !nameRegex.MatchString(queue.Name) {
+			continue
+		}
+
+		if queueType != "" && queue.Type != queueType {
+			continue
+		}
+
+		q, err := flattenQueueSettings(queue)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		q["name"] = queue.Name
+		q["vhost"] = queue.Vhost
+		q["type"] = queue.Type
+		q["node"] = queue.Node
+		q["state"] = queue.Status
+		q["messages"] = queue.Messages
+		q["consumers"] = queue.Consumers
+		q["effective_policy"] = queue.Policy
+
+		names = append(names, queue.Name)
+		result = append(result, q)
+	}
+
+	d.Set("names", names)
+	d.Set("queues", result)
+
+	d.SetId(vhost)
+
+	return diags
+}
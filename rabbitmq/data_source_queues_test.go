@@ -0,0 +1,86 @@
+package rabbitmq
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceQueues(t *testing.T) {
+	dataSourceName := "data.rabbitmq_queues.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceQueuesConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "names.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "names.0", "orders.quorum"),
+					resource.TestCheckResourceAttr(dataSourceName, "queues.0.type", "quorum"),
+					resource.TestCheckResourceAttr(dataSourceName, "queues.0.vhost", "testvhost"),
+					resource.TestCheckResourceAttr(dataSourceName, "queues.0.durable", "true"),
+					resource.TestCheckResourceAttr("data.rabbitmq_queues.all", "names.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceQueuesConfig = `
+resource "rabbitmq_vhost" "test" {
+    name = "testvhost"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user  = "guest"
+    vhost = rabbitmq_vhost.test.name
+    permissions {
+        configure = ".*"
+        write     = ".*"
+        read      = ".*"
+    }
+}
+
+resource "rabbitmq_queue" "classic" {
+    name  = "orders.classic"
+    vhost = rabbitmq_permissions.guest.vhost
+    settings {
+        durable = true
+    }
+}
+
+resource "rabbitmq_queue" "quorum" {
+    name  = "orders.quorum"
+    vhost = rabbitmq_permissions.guest.vhost
+    settings {
+        durable = true
+        arguments = {
+            "x-queue-type" = "quorum"
+        }
+    }
+}
+
+resource "rabbitmq_queue" "other" {
+    name  = "billing"
+    vhost = rabbitmq_permissions.guest.vhost
+    settings {
+        durable = true
+    }
+}
+
+data "rabbitmq_queues" "test" {
+    vhost      = rabbitmq_vhost.test.name
+    name_regex = "^orders\\."
+    type       = "quorum"
+
+    depends_on = [rabbitmq_queue.classic, rabbitmq_queue.quorum, rabbitmq_queue.other]
+}
+
+data "rabbitmq_queues" "all" {
+    vhost = rabbitmq_vhost.test.name
+
+    depends_on = [rabbitmq_queue.classic, rabbitmq_queue.quorum, rabbitmq_queue.other]
+}
+`
@@ -4,8 +4,6 @@ import (
 	"context"
 	"log"
 
-	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -38,7 +36,7 @@ func dataSourcesUser() *schema.Resource {
 func dataSourcesReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name := d.Get("name").(string)
 
@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"testing"
 
-	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -44,7 +42,7 @@ func testAccCheckDataSourceUserExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("data source ID is not set")
 		}
 
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		name := rs.Primary.Attributes["name"]
 
 		user, err := rmqc.GetUser(name)
@@ -71,7 +69,7 @@ func testAccCheckUserExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("user id not set")
 		}
 
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		name := rs.Primary.Attributes["name"]
 
 		userInfo, err := rmqc.GetUser(name)
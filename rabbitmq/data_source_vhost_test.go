@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"testing"
 
-	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -39,7 +37,7 @@ func testAccCheckDataSourceVhostExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("data source ID is not set")
 		}
 
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		vhost, err := rmqc.GetVhost(rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("Error retrieving vhost: %s", err)
@@ -64,7 +62,7 @@ func testAccCheckVhostExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("vhost id not set")
 		}
 
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		vhost, err := rmqc.GetVhost(rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("Error retrieving vhost: %s", err)
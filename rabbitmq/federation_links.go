@@ -0,0 +1,152 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+)
+
+// Federation links are started by the policies federating an exchange or a
+// queue from an upstream, so an upstream has no links until a policy uses it.
+
+const (
+	federationLinkStatusRunning = "running"
+
+	federationLinksPollInterval = 2 * time.Second
+)
+
+// federationLink is a link of a federated exchange or queue to an upstream.
+type federationLink struct {
+	Upstream string
+	Vhost    string
+	// Type is `exchange` or `queue`.
+	Type           string
+	LocalObject    string
+	UpstreamObject string
+	Status         string
+	Error          string
+	Node           string
+}
+
+// parseFederationLinks converts the links returned by the management API,
+// keeping the ones of upstream when it is not empty.
+func parseFederationLinks(links rabbithole.FederationLinkMap, upstream string) []federationLink {
+	str := func(m map[string]interface{}, key string) string {
+		s, _ := m[key].(string)
+		return s
+	}
+
+	result := make([]federationLink, 0, len(links))
+	for _, l := range links {
+		link := federationLink{
+			Upstream: str(l, "upstream"),
+			Vhost:    str(l, "vhost"),
+			Type:     str(l, "type"),
+			Status:   str(l, "status"),
+			Error:    str(l, "error"),
+			Node:     str(l, "node"),
+		}
+		if upstream != "" && link.Upstream != upstream {
+			continue
+		}
+
+		if link.Type == "queue" {
+			link.LocalObject = str(l, "queue")
+			link.UpstreamObject = str(l, "upstream_queue")
+		} else {
+			link.LocalObject = str(l, "exchange")
+			link.UpstreamObject = str(l, "upstream_exchange")
+		}
+
+		result = append(result, link)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Vhost != result[j].Vhost {
+			return result[i].Vhost < result[j].Vhost
+		}
+		if result[i].Upstream != result[j].Upstream {
+			return result[i].Upstream < result[j].Upstream
+		}
+		if result[i].Type != result[j].Type {
+			return result[i].Type < result[j].Type
+		}
+		return result[i].LocalObject < result[j].LocalObject
+	})
+
+	return result
+}
+
+// listFederationLinks returns the links of a vhost, or of all vhosts when
+// vhost is empty, keeping the ones of upstream when it is not empty.
+func listFederationLinks(rmqc *rabbithole.Client, vhost string, upstream string) ([]federationLink, error) {
+	var links rabbithole.FederationLinkMap
+	var err error
+	if vhost != "" {
+		links, err = rmqc.ListFederationLinksIn(vhost)
+	} else {
+		links, err = rmqc.ListFederationLinks()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFederationLinks(links, upstream), nil
+}
+
+// federationLinksNotRunning describes the links that are not running,
+// with their error when they have one.
+func federationLinksNotRunning(links []federationLink) []string {
+	var result []string
+	for _, link := range links {
+		if link.Status == federationLinkStatusRunning {
+			continue
+		}
+
+		description := fmt.Sprintf("%s %s (%s)", link.Type, link.LocalObject, link.Status)
+		if link.Error != "" {
+			description = fmt.Sprintf("%s %s (%s: %s)", link.Type, link.LocalObject, link.Status, link.Error)
+		}
+		result = append(result, description)
+	}
+
+	return result
+}
+
+// waitForFederationLinks polls the links of an upstream until it has links
+// and they are all running, and returns an error with the state of the
+// others, or the absence of links, on timeout.
+func waitForFederationLinks(rmqc *rabbithole.Client, vhost string, upstream string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		links, err := listFederationLinks(rmqc, vhost, upstream)
+		if err != nil {
+			return err
+		}
+
+		notRunning := federationLinksNotRunning(links)
+		if len(links) > 0 && len(notRunning) == 0 {
+			log.Printf("[DEBUG] RabbitMQ: The %d links of federation upstream %s@%s are running", len(links), upstream, vhost)
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			if len(links) == 0 {
+				return fmt.Errorf("Timeout waiting for the links of federation upstream %s@%s to be running: it has no links, no policy uses it", upstream, vhost)
+			}
+			return fmt.Errorf("Timeout waiting for the links of federation upstream %s@%s to be running: %s", upstream, vhost, strings.Join(notRunning, ", "))
+		}
+
+		if len(links) == 0 {
+			log.Printf("[DEBUG] RabbitMQ: Waiting for federation upstream %s@%s to have links, no policy uses it yet", upstream, vhost)
+		} else {
+			log.Printf("[DEBUG] RabbitMQ: Waiting for the links of federation upstream %s@%s to be running: %s", upstream, vhost, strings.Join(notRunning, ", "))
+		}
+		time.Sleep(federationLinksPollInterval)
+	}
+}
@@ -0,0 +1,96 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+)
+
+func TestParseFederationLinks(t *testing.T) {
+	links := rabbithole.FederationLinkMap{
+		{"upstream": "dc2", "vhost": "test", "type": "exchange", "exchange": "events", "upstream_exchange": "events", "status": "running", "node": "rabbit@a"},
+		{"upstream": "dc1", "vhost": "test", "type": "queue", "queue": "orders", "upstream_queue": "orders-dc1", "status": "error", "error": "{econnrefused}", "node": "rabbit@b"},
+		{"upstream": "dc1", "vhost": "test", "type": "exchange", "exchange": "events", "upstream_exchange": "events", "status": "starting", "node": "rabbit@a"},
+	}
+
+	expected := []federationLink{
+		{Upstream: "dc1", Vhost: "test", Type: "exchange", LocalObject: "events", UpstreamObject: "events", Status: "starting", Node: "rabbit@a"},
+		{Upstream: "dc1", Vhost: "test", Type: "queue", LocalObject: "orders", UpstreamObject: "orders-dc1", Status: "error", Error: "{econnrefused}", Node: "rabbit@b"},
+	}
+	parsed := parseFederationLinks(links, "dc1")
+	if !reflect.DeepEqual(parsed, expected) {
+		t.Errorf("Expected the links %#v, got %#v", expected, parsed)
+	}
+
+	notRunning := federationLinksNotRunning(parsed)
+	if expected := []string{"exchange events (starting)", "queue orders (error: {econnrefused})"}; !reflect.DeepEqual(notRunning, expected) {
+		t.Errorf("Expected the links %v not to be running, got %v", expected, notRunning)
+	}
+
+	if parsed := parseFederationLinks(links, ""); len(parsed) != 3 || parsed[2].Upstream != "dc2" {
+		t.Errorf("Expected the links of all upstreams, got %#v", parsed)
+	}
+
+	if notRunning := federationLinksNotRunning(parseFederationLinks(links, "dc2")); len(notRunning) != 0 {
+		t.Errorf("Expected the links of dc2 to be running, got %v", notRunning)
+	}
+}
+
+func TestWaitForFederationLinks(t *testing.T) {
+	// The link appears on the second poll, as when the policy using the
+	// upstream is created at the same time.
+	var polls int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&polls, 1) == 1 {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		fmt.Fprint(w, `[{"upstream": "dc1", "vhost": "test", "type": "exchange", "exchange": "events", "upstream_exchange": "events", "status": "running"}]`)
+	}))
+	defer api.Close()
+
+	rmqc, err := rabbithole.NewClient(api.URL, "guest", "guest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := waitForFederationLinks(rmqc, "test", "dc1", time.Minute); err != nil {
+		t.Errorf("Expected the wait to succeed once the link appears, got %s", err)
+	}
+	if polls := atomic.LoadInt32(&polls); polls != 2 {
+		t.Errorf("Expected the links to be polled twice, got %d", polls)
+	}
+
+	var cases = []struct {
+		links    string
+		expected string
+	}{
+		{`[]`, "it has no links, no policy uses it"},
+		{`[{"upstream": "dc2", "vhost": "test", "type": "exchange", "exchange": "events", "status": "running"}]`, "it has no links, no policy uses it"},
+		{`[{"upstream": "dc1", "vhost": "test", "type": "exchange", "exchange": "events", "status": "starting"}, {"upstream": "dc1", "vhost": "test", "type": "queue", "queue": "orders", "status": "error", "error": "{econnrefused}"}]`, "exchange events (starting), queue orders (error: {econnrefused})"},
+	}
+
+	for _, c := range cases {
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, c.links)
+		}))
+		rmqc, err := rabbithole.NewClient(api.URL, "guest", "guest")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = waitForFederationLinks(rmqc, "test", "dc1", 0)
+		api.Close()
+		if err == nil || !strings.HasSuffix(err.Error(), c.expected) {
+			t.Errorf("Expected the wait on the links %s to time out with %q, got %v", c.links, c.expected, err)
+		}
+	}
+}
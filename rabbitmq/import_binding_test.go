@@ -1,6 +1,7 @@
 package rabbitmq
 
 import (
+	"regexp"
 	"testing"
 
 	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
@@ -32,3 +33,125 @@ func TestAccBinding_importBasic(t *testing.T) {
 		},
 	})
 }
+
+func TestAccBinding_importByRoutingKey(t *testing.T) {
+	resourceName := "rabbitmq_binding.test"
+	var bindingInfo rabbithole.BindingInfo
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccBindingCheckDestroy(bindingInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBindingConfig_basic,
+				Check: testAccBindingCheck(
+					resourceName, &bindingInfo,
+				),
+			},
+
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "test/test/test/queue/#",
+				ImportStateVerify: true,
+			},
+
+			{
+				ResourceName:  resourceName,
+				ImportState:   true,
+				ImportStateId: "test/test/test/queue/missing",
+				ExpectError:   regexp.MustCompile("no binding from test to queue test in vhost test has the properties key or routing key missing"),
+			},
+		},
+	})
+}
+
+func TestAccBinding_importByRoutingKeyAndArguments(t *testing.T) {
+	resourceName := "rabbitmq_binding.all"
+	var bindingInfo rabbithole.BindingInfo
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccBindingCheckDestroy(bindingInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBindingConfig_sameRoutingKey,
+				Check: testAccBindingCheck(
+					resourceName, &bindingInfo,
+				),
+			},
+
+			{
+				ResourceName:  resourceName,
+				ImportState:   true,
+				ImportStateId: "test/test/test/queue/orders",
+				ExpectError:   regexp.MustCompile("2 bindings from test to queue test in vhost test have the routing key orders"),
+			},
+
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     `test/test/test/queue/orders/{"x-match":"all"}`,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccBindingConfig_sameRoutingKey = `
+resource "rabbitmq_vhost" "test" {
+    name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user = "guest"
+    vhost = "${rabbitmq_vhost.test.name}"
+    permissions {
+        configure = ".*"
+        write = ".*"
+        read = ".*"
+    }
+}
+
+resource "rabbitmq_exchange" "test" {
+    name = "test"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        type = "headers"
+        durable = false
+        auto_delete = true
+    }
+}
+
+resource "rabbitmq_queue" "test" {
+    name = "test"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        durable = true
+        auto_delete = false
+    }
+}
+
+resource "rabbitmq_binding" "all" {
+    source = "${rabbitmq_exchange.test.name}"
+    vhost = "${rabbitmq_vhost.test.name}"
+    destination = "${rabbitmq_queue.test.name}"
+    destination_type = "queue"
+    routing_key = "orders"
+    arguments = {
+      "x-match" = "all"
+    }
+}
+
+resource "rabbitmq_binding" "any" {
+    source = "${rabbitmq_exchange.test.name}"
+    vhost = "${rabbitmq_vhost.test.name}"
+    destination = "${rabbitmq_queue.test.name}"
+    destination_type = "queue"
+    routing_key = "orders"
+    arguments = {
+      "x-match" = "any"
+    }
+}`
@@ -0,0 +1,34 @@
+package rabbitmq
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccExchangeBindings_importBasic(t *testing.T) {
+	resourceName := "rabbitmq_exchange_bindings.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccExchangeBindingsCheckDestroy("test", "events"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccExchangeBindingsConfig(testAccExchangeBindingsConfig_twoBindings),
+				Check: testAccExchangeBindingsCheck(resourceName, []string{
+					"exchange:audit:#",
+					"queue:orders:orders.*",
+				}),
+			},
+
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Ignored destinations only exist in the configuration.
+				ImportStateVerifyIgnore: []string{"ignore_destination_patterns"},
+			},
+		},
+	})
+}
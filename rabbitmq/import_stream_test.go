@@ -0,0 +1,34 @@
+package rabbitmq
+
+import (
+	"testing"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccStream_importBasic(t *testing.T) {
+	resourceName := "rabbitmq_stream.test"
+	var stream rabbithole.QueueInfo
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamCheckDestroy(&stream),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStreamConfig("7D", 20000000),
+				Check: testAccStreamCheck(
+					resourceName, &stream,
+				),
+			},
+
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
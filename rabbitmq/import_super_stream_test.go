@@ -0,0 +1,34 @@
+package rabbitmq
+
+import (
+	"testing"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccSuperStream_importBasic(t *testing.T) {
+	resourceName := "rabbitmq_super_stream.test"
+	var exchange rabbithole.ExchangeInfo
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccSuperStreamCheckDestroy(&exchange),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSuperStreamConfig_partitions(2),
+				Check: testAccSuperStreamCheck(
+					resourceName, &exchange, []string{"0", "1"},
+				),
+			},
+
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,85 @@
+package rabbitmq
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+)
+
+// Some fields of the management API are not exposed by rabbit-hole. Requests
+// using them are sent directly, with the transport the client was created
+// with so that the TLS and proxy settings of the provider apply.
+
+var clientTransports sync.Map
+
+func registerClientTransport(rmqc *rabbithole.Client, transport http.RoundTripper) {
+	clientTransports.Store(rmqc, transport)
+}
+
+// managementRequest sends a request to the management API at path, relative
+// to /api/ and already escaped. body, if not nil, is sent as JSON and the
+// response is decoded into result, if not nil. Error responses are returned
+// as rabbithole.ErrorResponse, like rabbit-hole does.
+func managementRequest(rmqc *rabbithole.Client, method string, path string, body interface{}, result interface{}) (*http.Response, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	endpoint := fmt.Sprintf("%s/api/%s", strings.TrimSuffix(rmqc.Endpoint, "/"), path)
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(rmqc.Username, rmqc.Password)
+	req.Header.Set("Content-Type", "application/json")
+
+	httpc := &http.Client{}
+	if transport, ok := clientTransports.Load(rmqc); ok {
+		httpc.Transport = transport.(http.RoundTripper)
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: Management API request: %s %s", method, endpoint)
+
+	resp, err := httpc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("Error: API responded with a 401 Unauthorized")
+	}
+
+	// A 404 response to a DELETE request is a success, as with rabbit-hole.
+	if method == http.MethodDelete && resp.StatusCode == http.StatusNotFound {
+		return resp, nil
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		errorResponse := rabbithole.ErrorResponse{}
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			errorResponse.Message = fmt.Sprintf("Error %d from RabbitMQ: %s", resp.StatusCode, err)
+		}
+		errorResponse.StatusCode = resp.StatusCode
+		return nil, errorResponse
+	}
+
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
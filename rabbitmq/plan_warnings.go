@@ -0,0 +1,68 @@
+package rabbitmq
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The SDK does not let CustomizeDiff functions return warnings. The provider
+// server collects the warnings they add to the context of a plan and returns
+// them with the planned changes.
+
+type planWarningsKey struct{}
+
+type planWarnings struct {
+	mu    sync.Mutex
+	diags diag.Diagnostics
+}
+
+// addPlanWarning adds a warning to the plan of the context. Outside of
+// the provider server, e.g. in tests, the warning is only logged.
+func addPlanWarning(ctx context.Context, warning diag.Diagnostic) {
+	warning.Severity = diag.Warning
+
+	warnings, ok := ctx.Value(planWarningsKey{}).(*planWarnings)
+	if !ok {
+		log.Printf("[WARN] RabbitMQ: %s: %s", warning.Summary, warning.Detail)
+		return
+	}
+
+	warnings.mu.Lock()
+	defer warnings.mu.Unlock()
+	warnings.diags = append(warnings.diags, warning)
+}
+
+// GRPCProviderServer returns the server of the provider, which returns the
+// warnings of CustomizeDiff functions with the planned changes.
+func GRPCProviderServer() tfprotov5.ProviderServer {
+	return &providerServer{schema.NewGRPCProviderServer(Provider())}
+}
+
+type providerServer struct {
+	*schema.GRPCProviderServer
+}
+
+func (s *providerServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	warnings := &planWarnings{}
+	resp, err := s.GRPCProviderServer.PlanResourceChange(context.WithValue(ctx, planWarningsKey{}, warnings), req)
+	if resp == nil {
+		return resp, err
+	}
+
+	warnings.mu.Lock()
+	defer warnings.mu.Unlock()
+	for _, warning := range warnings.diags {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityWarning,
+			Summary:  warning.Summary,
+			Detail:   warning.Detail,
+		})
+	}
+
+	return resp, err
+}
@@ -0,0 +1,166 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestProviderServer_planWarnings plans a policy through the provider server
+// against a fake management API, with a queue whose policy it shadows.
+func TestProviderServer_planWarnings(t *testing.T) {
+	api := testManagementAPI("3.13.0")
+	defer api.Close()
+
+	definition := map[string]cty.Value{"max-length": cty.StringVal("1000")}
+	for _, warn := range []bool{false, true} {
+		diags := testProviderServerPlanPolicy(t, api.URL, warn, definition)
+
+		var warnings []string
+		for _, d := range diags {
+			if d.Severity != tfprotov5.DiagnosticSeverityWarning {
+				t.Fatalf("Unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+			}
+			warnings = append(warnings, d.Summary)
+		}
+
+		if !warn {
+			if len(warnings) != 0 {
+				t.Errorf("Expected no warnings without warn_on_policy_shadowing, got %v", warnings)
+			}
+			continue
+		}
+		if len(warnings) != 1 || warnings[0] != "The policy orders-high shadows other policies" {
+			t.Errorf("Expected a shadowing warning with warn_on_policy_shadowing, got %v", warnings)
+		}
+		if len(diags) == 1 && !strings.Contains(diags[0].Detail, "queue orders") {
+			t.Errorf("Expected the warning to list the queue orders, got %q", diags[0].Detail)
+		}
+	}
+}
+
+func TestProviderServer_mirroringWarning(t *testing.T) {
+	definition := map[string]cty.Value{"ha-mode": cty.StringVal("all")}
+
+	for version, expected := range map[string]int{"3.13.0": 0, "4.0.5": 1} {
+		api := testManagementAPI(version)
+		diags := testProviderServerPlanPolicy(t, api.URL, false, definition)
+		api.Close()
+
+		if len(diags) != expected {
+			t.Fatalf("Expected %d warnings on RabbitMQ %s, got %d", expected, version, len(diags))
+		}
+		if expected == 1 && diags[0].Summary != "Classic queue mirroring is not supported" {
+			t.Errorf("Expected a classic queue mirroring warning on RabbitMQ %s, got %s: %s", version, diags[0].Summary, diags[0].Detail)
+		}
+	}
+}
+
+// testManagementAPI returns a fake management API of a server of version,
+// with a queue orders on which the policy orders-low applies.
+func testManagementAPI(version string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch path := r.URL.EscapedPath(); {
+		case path == "/api/overview":
+			fmt.Fprintf(w, `{"rabbitmq_version": %q}`, version)
+		case strings.HasPrefix(path, "/api/policies/"):
+			fmt.Fprint(w, `[{"vhost": "/", "name": "orders-low", "pattern": "^orders", "apply-to": "queues", "priority": 0, "definition": {"max-length": 10}}]`)
+		case strings.HasPrefix(path, "/api/queues/"):
+			fmt.Fprint(w, `[{"vhost": "/", "name": "orders", "type": "classic"}]`)
+		case strings.HasPrefix(path, "/api/operator-policies/"), strings.HasPrefix(path, "/api/exchanges/"):
+			fmt.Fprint(w, `[]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": "Object Not Found", "reason": "Not Found"}`)
+		}
+	}))
+}
+
+// testProviderServerPlanPolicy configures a provider server and returns the
+// diagnostics of the plan of a new policy with definition.
+func testProviderServerPlanPolicy(t *testing.T, endpoint string, warn bool, definition map[string]cty.Value) []*tfprotov5.Diagnostic {
+	t.Helper()
+
+	ctx := context.Background()
+	server := GRPCProviderServer()
+
+	schemas, err := Provider().GetSchema(&terraform.ProviderSchemaRequest{ResourceTypes: []string{"rabbitmq_policy"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	providerConfig := testDynamicValue(t, testObjectVal(schemas.Provider.ImpliedType(), map[string]cty.Value{
+		"endpoint":                 cty.StringVal(endpoint),
+		"username":                 cty.StringVal("guest"),
+		"password":                 cty.StringVal("guest"),
+		"warn_on_policy_shadowing": cty.BoolVal(warn),
+	}))
+	configured, err := server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{
+		TerraformVersion: "1.9.0",
+		Config:           providerConfig,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(configured.Diagnostics) != 0 {
+		t.Fatalf("Unable to configure the provider: %s", configured.Diagnostics[0].Summary)
+	}
+
+	policyType := schemas.ResourceTypes["rabbitmq_policy"].ImpliedType()
+	settingsType := policyType.AttributeType("policy").ElementType()
+	config := testObjectVal(policyType, map[string]cty.Value{
+		"name":  cty.StringVal("orders-high"),
+		"vhost": cty.StringVal("/"),
+		"policy": cty.ListVal([]cty.Value{testObjectVal(settingsType, map[string]cty.Value{
+			"pattern":    cty.StringVal("^orders"),
+			"priority":   cty.NumberIntVal(10),
+			"apply_to":   cty.StringVal("queues"),
+			"definition": cty.MapVal(definition),
+		})}),
+	})
+
+	planned, err := server.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         "rabbitmq_policy",
+		PriorState:       testDynamicValue(t, cty.NullVal(policyType)),
+		ProposedNewState: testDynamicValue(t, config),
+		Config:           testDynamicValue(t, config),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return planned.Diagnostics
+}
+
+// testObjectVal returns an object of type ty with attrs, and null values for
+// the other attributes.
+func testObjectVal(ty cty.Type, attrs map[string]cty.Value) cty.Value {
+	values := make(map[string]cty.Value, len(ty.AttributeTypes()))
+	for name, attrType := range ty.AttributeTypes() {
+		if v, ok := attrs[name]; ok {
+			values[name] = v
+		} else {
+			values[name] = cty.NullVal(attrType)
+		}
+	}
+	return cty.ObjectVal(values)
+}
+
+func testDynamicValue(t *testing.T, v cty.Value) *tfprotov5.DynamicValue {
+	t.Helper()
+
+	b, err := msgpack.Marshal(v, v.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tfprotov5.DynamicValue{MsgPack: b}
+}
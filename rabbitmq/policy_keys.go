@@ -0,0 +1,438 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The keys of policy definitions known to RabbitMQ, with the objects they
+// apply to and the values they accept. Keys that are not in the catalog,
+// e.g. the ones of plugins, only cause a warning.
+
+// Policy targets, as in the `apply_to` of RabbitMQ 3.12 and later.
+const (
+	policyTargetClassicQueues = "classic_queues"
+	policyTargetQuorumQueues  = "quorum_queues"
+	policyTargetStreams       = "streams"
+	policyTargetExchanges     = "exchanges"
+)
+
+// policyApplyToValues are the values of `apply_to`. The ones selecting a
+// queue type need RabbitMQ 3.12 or later.
+var policyApplyToValues = []string{"all", "queues", "exchanges", policyTargetClassicQueues, policyTargetQuorumQueues, policyTargetStreams}
+
+// policyApplyToTargets maps the values of `apply_to` onto the objects
+// the policy applies to.
+var policyApplyToTargets = map[string][]string{
+	"all":                     {policyTargetClassicQueues, policyTargetQuorumQueues, policyTargetStreams, policyTargetExchanges},
+	"queues":                  {policyTargetClassicQueues, policyTargetQuorumQueues, policyTargetStreams},
+	policyTargetClassicQueues: {policyTargetClassicQueues},
+	policyTargetQuorumQueues:  {policyTargetQuorumQueues},
+	policyTargetStreams:       {policyTargetStreams},
+	policyTargetExchanges:     {policyTargetExchanges},
+}
+
+// policyUnknownValue is how Terraform passes the values of a map
+// that are not known yet to validation functions.
+const policyUnknownValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+type policyValueType int
+
+const (
+	policyValueString policyValueType = iota
+	policyValueInteger
+	// ha-params is a number of replicas or a list of node names,
+	// depending on ha-mode.
+	policyValueHaParams
+)
+
+type policyKey struct {
+	valueType policyValueType
+	allowed   []string
+	// deprecated are allowed values that only cause a warning.
+	deprecated []string
+	// valueTargets restricts allowed values to some of the targets.
+	valueTargets map[string][]string
+	targets      []string
+	// operator is whether the key can be set by operator policies.
+	operator bool
+}
+
+var (
+	policyTargetsQueues        = []string{policyTargetClassicQueues, policyTargetQuorumQueues}
+	policyTargetsAllQueues     = []string{policyTargetClassicQueues, policyTargetQuorumQueues, policyTargetStreams}
+	policyTargetsClassicQueues = []string{policyTargetClassicQueues}
+	policyTargetsQuorumQueues  = []string{policyTargetQuorumQueues}
+	policyTargetsStreams       = []string{policyTargetStreams}
+	policyTargetsFederation    = []string{policyTargetClassicQueues, policyTargetQuorumQueues, policyTargetExchanges}
+)
+
+var policyKeys = map[string]policyKey{
+	"max-length":                    {valueType: policyValueInteger, targets: policyTargetsQueues, operator: true},
+	"max-length-bytes":              {valueType: policyValueInteger, targets: policyTargetsAllQueues, operator: true},
+	"overflow":                      {allowed: []string{"drop-head", "reject-publish", "reject-publish-dlx"}, valueTargets: map[string][]string{"reject-publish-dlx": policyTargetsClassicQueues}, targets: policyTargetsQueues},
+	"message-ttl":                   {valueType: policyValueInteger, targets: policyTargetsQueues, operator: true},
+	"expires":                       {valueType: policyValueInteger, targets: policyTargetsQueues, operator: true},
+	"dead-letter-exchange":          {targets: policyTargetsQueues},
+	"dead-letter-routing-key":       {targets: policyTargetsQueues},
+	"consumer-timeout":              {valueType: policyValueInteger, targets: policyTargetsQueues},
+	"queue-leader-locator":          {allowed: []string{"client-local", "balanced"}, targets: policyTargetsAllQueues},
+	"queue-master-locator":          {allowed: []string{"client-local", "balanced", "min-masters", "random"}, deprecated: []string{"min-masters", "random"}, targets: policyTargetsQueues},
+	"queue-mode":                    {allowed: []string{"default", "lazy"}, targets: policyTargetsClassicQueues},
+	"queue-version":                 {valueType: policyValueInteger, allowed: []string{"1", "2"}, targets: policyTargetsClassicQueues},
+	"ha-mode":                       {allowed: []string{"all", "exactly", "nodes"}, targets: policyTargetsClassicQueues},
+	"ha-params":                     {valueType: policyValueHaParams, targets: policyTargetsClassicQueues},
+	"ha-sync-mode":                  {allowed: []string{"manual", "automatic"}, targets: policyTargetsClassicQueues},
+	"ha-sync-batch-size":            {valueType: policyValueInteger, targets: policyTargetsClassicQueues},
+	"ha-promote-on-shutdown":        {allowed: []string{"when-synced", "always"}, targets: policyTargetsClassicQueues},
+	"ha-promote-on-failure":         {allowed: []string{"when-synced", "always"}, targets: policyTargetsClassicQueues},
+	"dead-letter-strategy":          {allowed: []string{"at-most-once", "at-least-once"}, targets: policyTargetsQuorumQueues},
+	"delivery-limit":                {valueType: policyValueInteger, targets: policyTargetsQuorumQueues, operator: true},
+	"max-in-memory-length":          {valueType: policyValueInteger, targets: policyTargetsQuorumQueues, operator: true},
+	"max-in-memory-bytes":           {valueType: policyValueInteger, targets: policyTargetsQuorumQueues, operator: true},
+	"target-group-size":             {valueType: policyValueInteger, targets: policyTargetsQuorumQueues, operator: true},
+	"max-age":                       {targets: policyTargetsStreams},
+	"stream-max-segment-size-bytes": {valueType: policyValueInteger, targets: policyTargetsStreams},
+	"stream-filter-size-bytes":      {valueType: policyValueInteger, targets: policyTargetsStreams},
+	"alternate-exchange":            {targets: []string{policyTargetExchanges}},
+	"federation-upstream":           {targets: policyTargetsFederation},
+	"federation-upstream-set":       {targets: policyTargetsFederation},
+}
+
+// validatePolicyDefinition validates the `definition` map of a policy,
+// or of an operator policy when operator is true.
+func validatePolicyDefinition(operator bool) schema.SchemaValidateDiagFunc {
+	return func(v interface{}, path cty.Path) diag.Diagnostics {
+		definition, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		return checkPolicyDefinition(definition, false, operator, path)
+	}
+}
+
+// validatePolicyDefinitionJson validates the `definition_json` of a policy,
+// or of an operator policy when operator is true.
+func validatePolicyDefinitionJson(operator bool) schema.SchemaValidateDiagFunc {
+	return func(v interface{}, path cty.Path) diag.Diagnostics {
+		s, ok := v.(string)
+		if !ok {
+			return nil
+		}
+
+		definition, err := policyDefinitionFromJSON(s)
+		if err != nil {
+			return diag.Diagnostics{{
+				Severity:      diag.Error,
+				Summary:       "Invalid policy definition",
+				Detail:        err.Error(),
+				AttributePath: path,
+			}}
+		}
+
+		return checkPolicyDefinition(definition, true, operator, path)
+	}
+}
+
+func checkPolicyDefinition(definition map[string]interface{}, typed bool, operator bool, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, name := range sortedPolicyKeys(definition) {
+		value := definition[name]
+		key, ok := policyKeys[name]
+		if !ok {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Warning,
+				Summary:       fmt.Sprintf("Unknown policy key %q", name),
+				Detail:        fmt.Sprintf("The key %q is not a known policy key and may be ignored by RabbitMQ, unless a plugin handles it.", name),
+				AttributePath: path,
+			})
+			continue
+		}
+
+		if operator && !key.operator {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Policy key %q is not allowed in operator policies", name),
+				Detail:        fmt.Sprintf("Operator policies can only set %s.", strings.Join(operatorPolicyKeys(), ", ")),
+				AttributePath: path,
+			})
+			continue
+		}
+
+		if err := checkPolicyValue(name, key, value, typed, definition); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Invalid value for policy key %q", name),
+				Detail:        err.Error(),
+				AttributePath: path,
+			})
+			continue
+		}
+
+		if v, ok := value.(string); ok && stringInSlice(v, key.deprecated) {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Warning,
+				Summary:       fmt.Sprintf("Deprecated value for policy key %q", name),
+				Detail:        fmt.Sprintf("The value %q of %q is deprecated, RabbitMQ 3.12 and later use balanced instead.", v, name),
+				AttributePath: path,
+			})
+		}
+	}
+
+	return diags
+}
+
+// checkPolicyValue checks the value of a key of a policy definition.
+// Values of a `definition` map are strings, integers being sent as
+// numbers, whereas the values of `definition_json` keep their JSON type.
+func checkPolicyValue(name string, key policyKey, value interface{}, typed bool, definition map[string]interface{}) error {
+	// Values that are not known yet are checked on apply by RabbitMQ.
+	if value == policyUnknownValue {
+		return nil
+	}
+
+	var s string
+	switch key.valueType {
+	case policyValueString:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%q must be a string, got %v", name, value)
+		}
+		s = v
+
+	case policyValueInteger:
+		v, ok := policyIntegerValue(value, typed)
+		if !ok {
+			return fmt.Errorf("%q must be an integer, got %v", name, value)
+		}
+		s = strconv.FormatInt(v, 10)
+
+	case policyValueHaParams:
+		switch definition["ha-mode"] {
+		case "exactly":
+			if _, ok := policyIntegerValue(value, typed); !ok {
+				return fmt.Errorf("%q must be the number of replicas when ha-mode is exactly, got %v", name, value)
+			}
+		case "nodes":
+			if typed {
+				if _, ok := value.([]interface{}); !ok {
+					return fmt.Errorf("%q must be a list of node names when ha-mode is nodes, got %v", name, value)
+				}
+			}
+		}
+		return nil
+	}
+
+	if len(key.allowed) > 0 && !stringInSlice(s, key.allowed) {
+		return fmt.Errorf("%q must be one of %s, got %q", name, strings.Join(key.allowed, ", "), s)
+	}
+
+	return nil
+}
+
+func policyIntegerValue(value interface{}, typed bool) (int64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	case int:
+		return int64(v), true
+	case string:
+		// Strings of `definition_json` are sent as strings.
+		if typed {
+			return 0, false
+		}
+		i, err := strconv.ParseInt(v, 10, 64)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// customizePolicyDiff returns the CustomizeDiff function of policies, or of
+// operator policies when operator is true.
+func customizePolicyDiff(operator bool) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+		if err := checkPolicyDiff(ctx, diff, meta); err != nil {
+			return err
+		}
+
+		warnPolicyShadowing(ctx, diff, meta.(*providerMeta), operator)
+		return nil
+	}
+}
+
+// checkPolicyDiff checks that the server supports `apply_to` and that
+// the keys of the definition apply to the objects it selects, and warns
+// about classic queue mirroring keys.
+func checkPolicyDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown("policy.0.apply_to") {
+		return nil
+	}
+	applyTo := diff.Get("policy.0.apply_to").(string)
+
+	if _, ok := policyApplyToTargets[applyTo]; !ok {
+		return nil
+	}
+
+	if diff.HasChange("policy.0.apply_to") && applyTo != "all" && applyTo != "queues" && applyTo != "exchanges" {
+		version, err := serverVersion(meta.(*providerMeta).Client)
+		if err != nil {
+			return err
+		}
+		if !versionAtLeast(version, 3, 12) {
+			return fmt.Errorf("apply_to %s requires RabbitMQ 3.12 or later, connected to %s. Use queues instead", applyTo, version)
+		}
+	}
+
+	var definition map[string]interface{}
+	if diff.NewValueKnown("policy.0.definition_json") {
+		if v := diff.Get("policy.0.definition_json").(string); v != "" {
+			var err error
+			if definition, err = policyDefinitionFromJSON(v); err != nil {
+				return err
+			}
+		}
+	}
+	if definition == nil {
+		definition, _ = diff.Get("policy.0.definition").(map[string]interface{})
+	}
+
+	if err := checkPolicyTargets(definition, applyTo); err != nil {
+		return err
+	}
+
+	if mirroring := policyMirroringKeys(definition); len(mirroring) > 0 && diff.HasChange("policy") {
+		version, err := serverVersion(meta.(*providerMeta).Client)
+		if err != nil {
+			log.Printf("[WARN] RabbitMQ: Unable to check whether classic queue mirroring is supported: %s", err)
+			return nil
+		}
+		if warning := policyMirroringWarning(version, mirroring); warning != nil {
+			addPlanWarning(ctx, *warning)
+		}
+	}
+
+	return nil
+}
+
+// policyMirroringKeys returns the classic queue mirroring keys of a
+// definition.
+func policyMirroringKeys(definition map[string]interface{}) []string {
+	var keys []string
+	for _, name := range sortedPolicyKeys(definition) {
+		if strings.HasPrefix(name, "ha-") {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// policyMirroringWarning warns about classic queue mirroring keys when the
+// server is RabbitMQ 4.0 or later, which ignores them.
+func policyMirroringWarning(version string, keys []string) *diag.Diagnostic {
+	if len(keys) == 0 || !versionAtLeast(version, 4, 0) {
+		return nil
+	}
+
+	return &diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  "Classic queue mirroring is not supported",
+		Detail:   fmt.Sprintf("Classic queue mirroring was removed in RabbitMQ 4.0, connected to %s. The keys %s of the policy have no effect, use quorum queues or streams for replication instead.", version, strings.Join(keys, ", ")),
+	}
+}
+
+// validatePolicyPattern checks that a policy pattern is a valid regular
+// expression. RabbitMQ uses PCRE, so Perl syntax that Go does not support,
+// e.g. lookaheads, is left to the server.
+func validatePolicyPattern(v interface{}, k string) (warnings []string, errs []error) {
+	pattern, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	if _, err := regexp.Compile(pattern); err != nil {
+		var syntaxErr *syntax.Error
+		if errors.As(err, &syntaxErr) && (syntaxErr.Code == syntax.ErrInvalidPerlOp || syntaxErr.Code == syntax.ErrInvalidEscape) {
+			return nil, nil
+		}
+		return nil, []error{fmt.Errorf("%q: %s is not a valid regular expression: %v", k, pattern, err)}
+	}
+
+	return nil, nil
+}
+
+// checkPolicyTargets checks that the keys of a definition, and their values,
+// apply to the objects selected by applyTo.
+func checkPolicyTargets(definition map[string]interface{}, applyTo string) error {
+	targets := policyApplyToTargets[applyTo]
+
+	for _, name := range sortedPolicyKeys(definition) {
+		key, ok := policyKeys[name]
+		if !ok {
+			continue
+		}
+
+		if !policyKeyAppliesTo(key, targets) {
+			return fmt.Errorf("Policy key %q does not apply to %s, it applies to %s", name, applyTo, strings.Join(key.targets, ", "))
+		}
+
+		value := fmt.Sprint(definition[name])
+		if valueTargets, ok := key.valueTargets[value]; ok && !policyKeyAppliesTo(policyKey{targets: valueTargets}, targets) {
+			return fmt.Errorf("Value %q of policy key %q does not apply to %s, it applies to %s", value, name, applyTo, strings.Join(valueTargets, ", "))
+		}
+	}
+
+	return nil
+}
+
+func policyKeyAppliesTo(key policyKey, targets []string) bool {
+	for _, target := range targets {
+		if stringInSlice(target, key.targets) {
+			return true
+		}
+	}
+	return false
+}
+
+func operatorPolicyKeys() []string {
+	var keys []string
+	for name, key := range policyKeys {
+		if key.operator {
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedPolicyKeys(definition map[string]interface{}) []string {
+	keys := make([]string, 0, len(definition))
+	for name := range definition {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stringInSlice(s string, slice []string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package rabbitmq
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func TestValidatePolicyDefinition(t *testing.T) {
+	var cases = []struct {
+		definition map[string]interface{}
+		operator   bool
+		severity   []diag.Severity
+	}{
+		{map[string]interface{}{"max-length": "10000", "overflow": "reject-publish"}, false, nil},
+		{map[string]interface{}{"ha-mode": "nodes", "ha-params": "a,b"}, false, nil},
+		{map[string]interface{}{"max-lenght": "10000"}, false, []diag.Severity{diag.Warning}},
+		{map[string]interface{}{"max-length": "ten"}, false, []diag.Severity{diag.Error}},
+		{map[string]interface{}{"overflow": "drop-tail"}, false, []diag.Severity{diag.Error}},
+		{map[string]interface{}{"queue-version": "3"}, false, []diag.Severity{diag.Error}},
+		{map[string]interface{}{"ha-mode": "exactly", "ha-params": "two"}, false, []diag.Severity{diag.Error}},
+		{map[string]interface{}{"max-length": policyUnknownValue}, false, nil},
+		{map[string]interface{}{"max-length": "10000", "expires": "60000"}, true, nil},
+		{map[string]interface{}{"ha-mode": "all"}, true, []diag.Severity{diag.Error}},
+	}
+
+	for _, c := range cases {
+		diags := validatePolicyDefinition(c.operator)(c.definition, cty.Path{})
+		if len(diags) != len(c.severity) {
+			t.Errorf("validatePolicyDefinition(%v) returned %d diagnostics, expected %d: %v", c.definition, len(diags), len(c.severity), diags)
+			continue
+		}
+		for i, d := range diags {
+			if d.Severity != c.severity[i] {
+				t.Errorf("validatePolicyDefinition(%v) returned %v, expected severity %v", c.definition, d, c.severity[i])
+			}
+		}
+	}
+}
+
+func TestValidatePolicyDefinitionJson(t *testing.T) {
+	var cases = []struct {
+		definition string
+		severity   []diag.Severity
+	}{
+		{`{"ha-mode": "nodes", "ha-params": ["a", "b"], "max-length": 10000}`, nil},
+		{`{"federation-upstream": "1234"}`, nil},
+		{`{"max-length": "10000"}`, []diag.Severity{diag.Error}},
+		{`{"ha-mode": "nodes", "ha-params": "a,b"}`, []diag.Severity{diag.Error}},
+		{`{"message-ttl": 1.5}`, []diag.Severity{diag.Error}},
+		{`not json`, []diag.Severity{diag.Error}},
+	}
+
+	for _, c := range cases {
+		diags := validatePolicyDefinitionJson(false)(c.definition, cty.Path{})
+		if len(diags) != len(c.severity) {
+			t.Errorf("validatePolicyDefinitionJson(%s) returned %d diagnostics, expected %d: %v", c.definition, len(diags), len(c.severity), diags)
+			continue
+		}
+		for i, d := range diags {
+			if d.Severity != c.severity[i] {
+				t.Errorf("validatePolicyDefinitionJson(%s) returned %v, expected severity %v", c.definition, d, c.severity[i])
+			}
+		}
+	}
+}
+
+func TestValidatePolicyPattern(t *testing.T) {
+	var cases = []struct {
+		pattern string
+		valid   bool
+	}{
+		{".*", true},
+		{"^amq\\.", true},
+		{"^(?!amq\\.).*", true},
+		{"^orders\\Z", true},
+		{"^(orders", false},
+		{"[a-z", false},
+		{"*orders", false},
+	}
+
+	for _, c := range cases {
+		_, errs := validatePolicyPattern(c.pattern, "policy.0.pattern")
+		if valid := len(errs) == 0; valid != c.valid {
+			t.Errorf("validatePolicyPattern(%q) returned %v, expected valid to be %v", c.pattern, errs, c.valid)
+		}
+	}
+}
+
+func TestPolicyMirroringWarning(t *testing.T) {
+	var cases = []struct {
+		version    string
+		definition map[string]interface{}
+		warning    bool
+	}{
+		{"4.0.5", map[string]interface{}{"ha-mode": "all", "ha-sync-mode": "automatic"}, true},
+		{"4.1.0-rc.1", map[string]interface{}{"ha-mode": "exactly", "ha-params": 2}, true},
+		{"3.13.7", map[string]interface{}{"ha-mode": "all"}, false},
+		{"4.0.5", map[string]interface{}{"max-length": 1000}, false},
+		{"", map[string]interface{}{"ha-mode": "all"}, false},
+	}
+
+	for _, c := range cases {
+		warning := policyMirroringWarning(c.version, policyMirroringKeys(c.definition))
+		if (warning != nil) != c.warning {
+			t.Errorf("policyMirroringWarning(%q, %v) returned %v, expected a warning to be %v", c.version, c.definition, warning, c.warning)
+		}
+	}
+
+	warning := policyMirroringWarning("4.0.5", policyMirroringKeys(map[string]interface{}{"ha-sync-mode": "automatic", "ha-mode": "all"}))
+	if warning == nil || !strings.Contains(warning.Detail, "ha-mode, ha-sync-mode") {
+		t.Errorf("Expected the warning to list the sorted mirroring keys, got %v", warning)
+	}
+}
+
+func TestPolicyKeyValues(t *testing.T) {
+	var cases = []struct {
+		definition map[string]interface{}
+		severity   []diag.Severity
+	}{
+		{map[string]interface{}{"queue-master-locator": "client-local"}, nil},
+		{map[string]interface{}{"queue-master-locator": "balanced"}, nil},
+		{map[string]interface{}{"queue-master-locator": "min-masters"}, []diag.Severity{diag.Warning}},
+		{map[string]interface{}{"queue-master-locator": "random"}, []diag.Severity{diag.Warning}},
+		{map[string]interface{}{"queue-master-locator": "least-leaders"}, []diag.Severity{diag.Error}},
+		{map[string]interface{}{"overflow": "drop-head"}, nil},
+		{map[string]interface{}{"overflow": "reject-publish"}, nil},
+		{map[string]interface{}{"overflow": "reject-publish-dlx"}, nil},
+		{map[string]interface{}{"overflow": "drop-tail"}, []diag.Severity{diag.Error}},
+	}
+
+	for _, c := range cases {
+		diags := validatePolicyDefinition(false)(c.definition, cty.Path{})
+		if len(diags) != len(c.severity) {
+			t.Errorf("validatePolicyDefinition(%v) returned %d diagnostics, expected %d: %v", c.definition, len(diags), len(c.severity), diags)
+			continue
+		}
+		for i, d := range diags {
+			if d.Severity != c.severity[i] {
+				t.Errorf("validatePolicyDefinition(%v) returned %v, expected severity %v", c.definition, d, c.severity[i])
+			}
+		}
+	}
+}
+
+func TestCheckPolicyTargets(t *testing.T) {
+	var cases = []struct {
+		definition map[string]interface{}
+		applyTo    string
+		valid      bool
+	}{
+		{map[string]interface{}{"overflow": "reject-publish-dlx"}, policyTargetClassicQueues, true},
+		{map[string]interface{}{"overflow": "reject-publish-dlx"}, "queues", true},
+		{map[string]interface{}{"overflow": "reject-publish-dlx"}, policyTargetQuorumQueues, false},
+		{map[string]interface{}{"overflow": "reject-publish"}, policyTargetQuorumQueues, true},
+		{map[string]interface{}{"overflow": "drop-head"}, policyTargetQuorumQueues, true},
+		{map[string]interface{}{"overflow": "drop-head"}, policyTargetStreams, false},
+		{map[string]interface{}{"queue-master-locator": "client-local"}, policyTargetClassicQueues, true},
+		{map[string]interface{}{"queue-master-locator": "balanced"}, policyTargetQuorumQueues, true},
+		{map[string]interface{}{"queue-master-locator": "balanced"}, policyTargetStreams, false},
+		{map[string]interface{}{"queue-master-locator": "balanced"}, policyTargetExchanges, false},
+		{map[string]interface{}{"x-plugin-key": "value"}, policyTargetExchanges, true},
+	}
+
+	for _, c := range cases {
+		err := checkPolicyTargets(c.definition, c.applyTo)
+		if valid := err == nil; valid != c.valid {
+			t.Errorf("checkPolicyTargets(%v, %s) returned %v, expected valid to be %v", c.definition, c.applyTo, err, c.valid)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// RabbitMQ applies to a queue or an exchange the matching policy with the
+// greatest priority, and to a queue the matching operator policy with the
+// greatest priority. The definitions of the two are merged, the smaller
+// value winning for the keys they both set.
+
+// policyCandidate is a policy or an operator policy of a vhost.
+type policyCandidate struct {
+	Name       string
+	Operator   bool
+	Pattern    string
+	ApplyTo    string
+	Priority   int
+	Definition map[string]interface{}
+}
+
+type effectivePolicy struct {
+	Policy         *policyCandidate
+	OperatorPolicy *policyCandidate
+	Definition     map[string]interface{}
+	// Shadowed are the matching policies and operator policies
+	// that do not apply because another one takes precedence.
+	Shadowed []policyCandidate
+	// Unevaluated are the policies whose pattern Go can not evaluate,
+	// e.g. because of a lookahead.
+	Unevaluated []policyCandidate
+}
+
+// listPolicyCandidates returns the policies and operator policies of a vhost.
+func listPolicyCandidates(rmqc *rabbithole.Client, vhost string) ([]policyCandidate, error) {
+	policies, err := rmqc.ListPoliciesIn(vhost)
+	if err != nil {
+		return nil, err
+	}
+
+	operatorPolicies, err := rmqc.ListOperatorPoliciesIn(vhost)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]policyCandidate, 0, len(policies)+len(operatorPolicies))
+	for _, p := range policies {
+		candidates = append(candidates, policyCandidate{
+			Name:       p.Name,
+			Pattern:    p.Pattern,
+			ApplyTo:    p.ApplyTo,
+			Priority:   p.Priority,
+			Definition: p.Definition,
+		})
+	}
+	for _, p := range operatorPolicies {
+		candidates = append(candidates, policyCandidate{
+			Name:       p.Name,
+			Operator:   true,
+			Pattern:    p.Pattern,
+			ApplyTo:    p.ApplyTo,
+			Priority:   p.Priority,
+			Definition: p.Definition,
+		})
+	}
+
+	return candidates, nil
+}
+
+// policyTarget returns the policy target of an object: exchanges, or the
+// target of the queue type for queues.
+func policyTarget(kind string, queueType string) string {
+	if kind == "exchange" {
+		return policyTargetExchanges
+	}
+
+	switch queueType {
+	case "quorum":
+		return policyTargetQuorumQueues
+	case "stream":
+		return policyTargetStreams
+	default:
+		return policyTargetClassicQueues
+	}
+}
+
+// resolveEffectivePolicy returns the policies applying to the object named
+// name, of the given policy target, and its effective definition.
+func resolveEffectivePolicy(candidates []policyCandidate, name string, target string) effectivePolicy {
+	var result effectivePolicy
+	var policies, operatorPolicies []policyCandidate
+
+	for _, candidate := range candidates {
+		if !stringInSlice(target, policyApplyToTargets[candidate.ApplyTo]) {
+			continue
+		}
+
+		// Operator policies only apply to queues, whatever their apply_to.
+		if candidate.Operator && target == policyTargetExchanges {
+			continue
+		}
+
+		pattern, err := regexp.Compile(candidate.Pattern)
+		if err != nil {
+			result.Unevaluated = append(result.Unevaluated, candidate)
+			continue
+		}
+		if !pattern.MatchString(name) {
+			continue
+		}
+
+		if candidate.Operator {
+			operatorPolicies = append(operatorPolicies, candidate)
+		} else {
+			policies = append(policies, candidate)
+		}
+	}
+
+	// Ties are broken by name, RabbitMQ does not define which one wins.
+	byPrecedence := func(list []policyCandidate) {
+		sort.SliceStable(list, func(i, j int) bool {
+			if list[i].Priority != list[j].Priority {
+				return list[i].Priority > list[j].Priority
+			}
+			return list[i].Name < list[j].Name
+		})
+	}
+	byPrecedence(policies)
+	byPrecedence(operatorPolicies)
+
+	result.Definition = make(map[string]interface{})
+	if len(policies) > 0 {
+		result.Policy = &policies[0]
+		result.Shadowed = append(result.Shadowed, policies[1:]...)
+		for key, value := range policies[0].Definition {
+			result.Definition[key] = value
+		}
+	}
+	if len(operatorPolicies) > 0 {
+		result.OperatorPolicy = &operatorPolicies[0]
+		result.Shadowed = append(result.Shadowed, operatorPolicies[1:]...)
+		for key, value := range operatorPolicies[0].Definition {
+			result.Definition[key] = mergeOperatorPolicyValue(result.Definition[key], value)
+		}
+	}
+
+	return result
+}
+
+// mergeOperatorPolicyValue returns the value of a key set by an operator
+// policy, and maybe by a policy. Operator policies set limits, so the
+// smaller of two numbers wins.
+func mergeOperatorPolicyValue(policyValue interface{}, operatorValue interface{}) interface{} {
+	p, ok := policyNumber(policyValue)
+	if !ok {
+		return operatorValue
+	}
+	o, ok := policyNumber(operatorValue)
+	if !ok || p >= o {
+		return operatorValue
+	}
+	return policyValue
+}
+
+func policyNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// policyNames returns the names of policies.
+func policyNames(candidates []policyCandidate) []string {
+	names := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// maxShadowedObjects is the number of objects listed by shadowing warnings.
+const maxShadowedObjects = 20
+
+// warnPolicyShadowing adds a plan warning when the planned policy takes
+// precedence over other policies on existing queues and exchanges, when
+// `warn_on_policy_shadowing` is enabled.
+func warnPolicyShadowing(ctx context.Context, diff *schema.ResourceDiff, meta *providerMeta, operator bool) {
+	if !meta.warnOnPolicyShadowing {
+		return
+	}
+	if diff.Id() != "" && !diff.HasChange("policy") {
+		return
+	}
+	for _, key := range []string{"name", "vhost", "policy.0.pattern", "policy.0.apply_to", "policy.0.priority"} {
+		if !diff.NewValueKnown(key) {
+			return
+		}
+	}
+
+	planned := policyCandidate{
+		Name:     diff.Get("name").(string),
+		Operator: operator,
+		Pattern:  diff.Get("policy.0.pattern").(string),
+		ApplyTo:  diff.Get("policy.0.apply_to").(string),
+		Priority: diff.Get("policy.0.priority").(int),
+	}
+	vhost := diff.Get("vhost").(string)
+
+	changes, err := policyShadowingChanges(meta.Client, vhost, planned)
+	if err != nil {
+		log.Printf("[WARN] RabbitMQ: Unable to check whether policy %s@%s shadows other policies: %s", planned.Name, vhost, err)
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	kind := "policy"
+	if operator {
+		kind = "operator policy"
+	}
+
+	listed := changes
+	if len(listed) > maxShadowedObjects {
+		listed = listed[:maxShadowedObjects]
+	}
+	detail := fmt.Sprintf("The %s %s in vhost %s takes precedence over other policies on %d existing objects, whose effective policy would change: %s", kind, planned.Name, vhost, len(changes), strings.Join(listed, ", "))
+	if len(changes) > len(listed) {
+		detail += fmt.Sprintf(", and %d more", len(changes)-len(listed))
+	}
+
+	addPlanWarning(ctx, diag.Diagnostic{
+		Summary: fmt.Sprintf("The %s %s shadows other policies", kind, planned.Name),
+		Detail:  detail + ".",
+	})
+}
+
+// policyShadowingChanges returns the queues and exchanges of the vhost whose
+// policy would change from another policy to the planned one.
+func policyShadowingChanges(rmqc *rabbithole.Client, vhost string, planned policyCandidate) ([]string, error) {
+	candidates, err := listPolicyCandidates(rmqc, vhost)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var objects []policyObject
+
+	queues, err := rmqc.ListQueuesIn(vhost)
+	if err != nil {
+		return nil, err
+	}
+	for _, queue := range queues {
+		objects = append(objects, policyObject{"queue", queue.Name, policyTarget("queue", queue.Type)})
+	}
+
+	if !planned.Operator {
+		exchanges, err := rmqc.ListExchangesIn(vhost)
+		if err != nil {
+			return nil, err
+		}
+		for _, exchange := range exchanges {
+			if exchange.Name == "" {
+				continue
+			}
+			objects = append(objects, policyObject{"exchange", exchange.Name, policyTargetExchanges})
+		}
+	}
+
+	return shadowedPolicyObjects(candidates, planned, objects), nil
+}
+
+// policyObject is a queue or an exchange policies may apply to.
+type policyObject struct {
+	kind   string
+	name   string
+	target string
+}
+
+// shadowedPolicyObjects returns the objects whose policy would change from
+// another policy to the planned one, which replaces the current version of
+// the policy among the candidates.
+func shadowedPolicyObjects(candidates []policyCandidate, planned policyCandidate, objects []policyObject) []string {
+	next := []policyCandidate{planned}
+	for _, candidate := range candidates {
+		if candidate.Name != planned.Name || candidate.Operator != planned.Operator {
+			next = append(next, candidate)
+		}
+	}
+
+	var changes []string
+	for _, object := range objects {
+		before := resolvedPolicyName(resolveEffectivePolicy(candidates, object.name, object.target), planned.Operator)
+		after := resolvedPolicyName(resolveEffectivePolicy(next, object.name, object.target), planned.Operator)
+		if after == planned.Name && before != "" && before != planned.Name {
+			changes = append(changes, fmt.Sprintf("%s %s (from %s)", object.kind, object.name, before))
+		}
+	}
+
+	return changes
+}
+
+func resolvedPolicyName(effective effectivePolicy, operator bool) string {
+	p := effective.Policy
+	if operator {
+		p = effective.OperatorPolicy
+	}
+	if p == nil {
+		return ""
+	}
+	return p.Name
+}
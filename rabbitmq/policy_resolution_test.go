@@ -0,0 +1,96 @@
+package rabbitmq
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveEffectivePolicy(t *testing.T) {
+	candidates := []policyCandidate{
+		{Name: "all", Pattern: ".*", ApplyTo: "all", Priority: 0, Definition: map[string]interface{}{"max-length": float64(100)}},
+		{Name: "orders", Pattern: "^orders", ApplyTo: "queues", Priority: 10, Definition: map[string]interface{}{"dead-letter-exchange": "dlx", "max-length": float64(5000)}},
+		{Name: "quorum", Pattern: ".*", ApplyTo: "quorum_queues", Priority: 20, Definition: map[string]interface{}{"delivery-limit": float64(10)}},
+		{Name: "lookahead", Pattern: "^(?!amq\\.)", ApplyTo: "all", Priority: 30, Definition: map[string]interface{}{}},
+		{Name: "limits", Operator: true, Pattern: ".*", ApplyTo: "queues", Priority: 0, Definition: map[string]interface{}{"max-length": float64(1000), "expires": float64(60000)}},
+		{Name: "weak-limits", Operator: true, Pattern: ".*", ApplyTo: "queues", Priority: 0, Definition: map[string]interface{}{"max-length": float64(2000)}},
+		{Name: "all-limits", Operator: true, Pattern: "^orders", ApplyTo: "all", Priority: -10, Definition: map[string]interface{}{"max-length": float64(3000)}},
+	}
+
+	effective := resolveEffectivePolicy(candidates, "orders", policyTargetClassicQueues)
+	if effective.Policy == nil || effective.Policy.Name != "orders" {
+		t.Errorf("Expected the policy orders to apply, got %#v", effective.Policy)
+	}
+	if effective.OperatorPolicy == nil || effective.OperatorPolicy.Name != "limits" {
+		t.Errorf("Expected the operator policy limits to apply, got %#v", effective.OperatorPolicy)
+	}
+	expected := map[string]interface{}{"dead-letter-exchange": "dlx", "max-length": float64(1000), "expires": float64(60000)}
+	if !reflect.DeepEqual(effective.Definition, expected) {
+		t.Errorf("Expected the effective definition %v, got %v", expected, effective.Definition)
+	}
+	if names := policyNames(effective.Shadowed); !reflect.DeepEqual(names, []string{"all", "weak-limits", "all-limits"}) {
+		t.Errorf("Expected the policies all, weak-limits and all-limits to be shadowed, got %v", names)
+	}
+	if names := policyNames(effective.Unevaluated); !reflect.DeepEqual(names, []string{"lookahead"}) {
+		t.Errorf("Expected the policy lookahead not to be evaluated, got %v", names)
+	}
+
+	effective = resolveEffectivePolicy(candidates, "orders", policyTargetQuorumQueues)
+	if effective.Policy == nil || effective.Policy.Name != "quorum" {
+		t.Errorf("Expected the policy quorum to apply to a quorum queue, got %#v", effective.Policy)
+	}
+
+	effective = resolveEffectivePolicy(candidates, "orders", policyTargetExchanges)
+	if effective.Policy == nil || effective.Policy.Name != "all" {
+		t.Errorf("Expected the policy all to apply to an exchange, got %#v", effective.Policy)
+	}
+	// all-limits applies to all, but operator policies never apply to exchanges.
+	if effective.OperatorPolicy != nil {
+		t.Errorf("Expected no operator policy to apply to an exchange, got %#v", effective.OperatorPolicy)
+	}
+	if expected := map[string]interface{}{"max-length": float64(100)}; !reflect.DeepEqual(effective.Definition, expected) {
+		t.Errorf("Expected the effective definition %v of an exchange, got %v", expected, effective.Definition)
+	}
+	if len(effective.Shadowed) != 0 {
+		t.Errorf("Expected no policy to be shadowed, got %v", policyNames(effective.Shadowed))
+	}
+}
+
+func TestShadowedPolicyObjects(t *testing.T) {
+	candidates := []policyCandidate{
+		{Name: "dlx", Pattern: "^orders", ApplyTo: "queues", Priority: 10},
+		{Name: "all", Pattern: ".*", ApplyTo: "all", Priority: 0},
+		{Name: "limits", Operator: true, Pattern: ".*", ApplyTo: "queues", Priority: 0},
+	}
+	objects := []policyObject{
+		{"queue", "orders", policyTargetClassicQueues},
+		{"queue", "orders-archive", policyTargetQuorumQueues},
+		{"queue", "invoices", policyTargetClassicQueues},
+		{"exchange", "orders", policyTargetExchanges},
+	}
+
+	planned := policyCandidate{Name: "orders-ttl", Pattern: "^orders$", ApplyTo: "queues", Priority: 20}
+	changes := shadowedPolicyObjects(candidates, planned, objects)
+	if expected := []string{"queue orders (from dlx)"}; !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %v to change policy, got %v", expected, changes)
+	}
+
+	// A policy of a lower priority does not shadow the existing ones.
+	planned = policyCandidate{Name: "orders-ttl", Pattern: "^orders", ApplyTo: "all", Priority: 5}
+	changes = shadowedPolicyObjects(candidates, planned, objects)
+	if expected := []string{"exchange orders (from all)"}; !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %v to change policy, got %v", expected, changes)
+	}
+
+	// The current version of a policy is replaced by the planned one.
+	planned = policyCandidate{Name: "all", Pattern: ".*", ApplyTo: "all", Priority: 20}
+	changes = shadowedPolicyObjects(candidates, planned, objects)
+	if expected := []string{"queue orders (from dlx)", "queue orders-archive (from dlx)"}; !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %v to change policy, got %v", expected, changes)
+	}
+
+	planned = policyCandidate{Name: "strict-limits", Operator: true, Pattern: "^invoices$", ApplyTo: "queues", Priority: 10}
+	changes = shadowedPolicyObjects(candidates, planned, objects)
+	if expected := []string{"queue invoices (from limits)"}; !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %v to change operator policy, got %v", expected, changes)
+	}
+}
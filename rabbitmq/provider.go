@@ -95,31 +95,58 @@ func Provider() *schema.Provider {
 				Description: "The URL of a proxy through which to send HTTP requests to the RabbitMQ server. This can also be sourced from the `RABBITMQ_PROXY` Environment Variable. If not set, the default `HTTP_PROXY`/`HTTPS_PROXY` will be used instead.",
 				DefaultFunc: schema.EnvDefaultFunc("RABBITMQ_PROXY", ""),
 			},
+
+			"warn_on_policy_shadowing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Warn during plan when a new or changed policy or operator policy takes precedence over other policies on existing queues and exchanges, listing the objects whose effective policy would change.",
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"rabbitmq_binding":             resourceBinding(),
-			"rabbitmq_exchange":            resourceExchange(),
-			"rabbitmq_permissions":         resourcePermissions(),
-			"rabbitmq_topic_permissions":   resourceTopicPermissions(),
-			"rabbitmq_federation_upstream": resourceFederationUpstream(),
-			"rabbitmq_operator_policy":     resourceOperatorPolicy(),
-			"rabbitmq_policy":              resourcePolicy(),
-			"rabbitmq_queue":               resourceQueue(),
-			"rabbitmq_user":                resourceUser(),
-			"rabbitmq_vhost":               resourceVhost(),
-			"rabbitmq_shovel":              resourceShovel(),
+			"rabbitmq_binding":                 resourceBinding(),
+			"rabbitmq_exchange":                resourceExchange(),
+			"rabbitmq_exchange_bindings":       resourceExchangeBindings(),
+			"rabbitmq_permissions":             resourcePermissions(),
+			"rabbitmq_topic_permissions":       resourceTopicPermissions(),
+			"rabbitmq_federation_upstream":     resourceFederationUpstream(),
+			"rabbitmq_federation_upstream_set": resourceFederationUpstreamSet(),
+			"rabbitmq_operator_policy":         resourceOperatorPolicy(),
+			"rabbitmq_policy":                  resourcePolicy(),
+			"rabbitmq_queue":                   resourceQueue(),
+			"rabbitmq_user":                    resourceUser(),
+			"rabbitmq_vhost":                   resourceVhost(),
+			"rabbitmq_shovel":                  resourceShovel(),
+			"rabbitmq_stream":                  resourceStream(),
+			"rabbitmq_super_stream":            resourceSuperStream(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"rabbitmq_exchange": dataSourcesExchange(),
-			"rabbitmq_user":     dataSourcesUser(),
-			"rabbitmq_vhost":    dataSourcesVhost(),
+			"rabbitmq_bindings":         dataSourcesBindings(),
+			"rabbitmq_effective_policy": dataSourcesEffectivePolicy(),
+			"rabbitmq_exchange":         dataSourcesExchange(),
+			"rabbitmq_exchanges":        dataSourcesExchanges(),
+			"rabbitmq_federation_links": dataSourcesFederationLinks(),
+			"rabbitmq_policies":         dataSourcesPolicies(),
+			"rabbitmq_queue":            dataSourcesQueue(),
+			"rabbitmq_queues":           dataSourcesQueues(),
+			"rabbitmq_user":             dataSourcesUser(),
+			"rabbitmq_vhost":            dataSourcesVhost(),
 		},
 
 		ConfigureFunc: providerConfigure,
 	}
 }
 
+// providerMeta is passed to resources and data sources: the client of the
+// management API and the settings of the provider which are not about
+// connecting to it.
+type providerMeta struct {
+	*rabbithole.Client
+
+	warnOnPolicyShadowing bool
+}
+
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 
 	var username = d.Get("username").(string)
@@ -183,5 +210,10 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		return nil, err
 	}
 
-	return rmqc, nil
+	registerClientTransport(rmqc, transport)
+
+	return &providerMeta{
+		Client:                rmqc,
+		warnOnPolicyShadowing: d.Get("warn_on_policy_shadowing").(bool),
+	}, nil
 }
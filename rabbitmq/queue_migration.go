@@ -0,0 +1,329 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+)
+
+// The `migrate` recreate strategy of rabbitmq_queue parks the messages and
+// bindings of a queue on a temporary queue while the queue is declared again
+// with its new settings. Messages are moved with dynamic shovels that delete
+// themselves once the messages present at their start have been transferred.
+
+const queueMigrationPollInterval = 2 * time.Second
+
+// queueMigration records the progress of a migration, which is returned
+// with its result.
+type queueMigration struct {
+	steps []string
+}
+
+func (m *queueMigration) step(format string, a ...interface{}) {
+	step := fmt.Sprintf(format, a...)
+	log.Printf("[INFO] RabbitMQ: %s", step)
+	m.steps = append(m.steps, step)
+}
+
+func (m *queueMigration) progress() string {
+	return strings.Join(m.steps, "\n")
+}
+
+func migrateQueue(d *schema.ResourceData, rmqc *rabbithole.Client, vhost string, name string, settingsMap map[string]interface{}) diag.Diagnostics {
+	m := &queueMigration{}
+	if err := m.migrate(d, rmqc, vhost, name, settingsMap); err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  err.Error(),
+			Detail:   fmt.Sprintf("Progress of the migration of queue %s@%s:\n%s", name, vhost, m.progress()),
+		}}
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Queue %s@%s was migrated to its new settings", name, vhost),
+		Detail:   m.progress(),
+	}}
+}
+
+func (m *queueMigration) migrate(d *schema.ResourceData, rmqc *rabbithole.Client, vhost string, name string, settingsMap map[string]interface{}) error {
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutUpdate))
+	temporary := fmt.Sprintf("%s.terraform-migration", name)
+
+	// The settings the queue has, to declare it again when the new ones
+	// are rejected after all.
+	queue, err := rmqc.GetQueue(vhost, name)
+	if err != nil {
+		return err
+	}
+	previous := map[string]interface{}{
+		"durable":     queue.Durable,
+		"auto_delete": queue.AutoDelete,
+		"arguments":   map[string]interface{}(queue.Arguments),
+	}
+
+	bindings, err := listQueueSourceBindings(rmqc, vhost, name)
+	if err != nil {
+		return err
+	}
+
+	if err := checkQueueSettings(rmqc, vhost, name, settingsMap); err != nil {
+		return err
+	}
+	m.step("Checked the new settings of queue %s", name)
+
+	// The previous settings stay in state until the queue has been declared again.
+	d.Partial(true)
+
+	if err := declareQueue(rmqc, vhost, temporary, map[string]interface{}{"durable": true}); err != nil {
+		return err
+	}
+	m.step("Declared temporary queue %s", temporary)
+
+	if err := moveQueueBindings(rmqc, vhost, bindings, name, temporary); err != nil {
+		return m.abort(rmqc, vhost, name, temporary, bindings, err)
+	}
+	m.step("Moved %d bindings from queue %s to %s", len(bindings), name, temporary)
+
+	moved, err := moveQueueMessages(rmqc, vhost, name, temporary, deadline)
+	if err != nil {
+		// The messages already moved are shovelled back by abort.
+		if stopErr := deleteMigrationShovel(rmqc, vhost, name); stopErr != nil {
+			return fmt.Errorf("Migration of queue %s failed (%s) and the shovel moving its messages to the temporary queue %s could not be stopped: %w", name, err, temporary, stopErr)
+		}
+		return m.abort(rmqc, vhost, name, temporary, bindings, err)
+	}
+	m.step("Moved %d messages from queue %s to %s", moved, name, temporary)
+
+	log.Printf("[DEBUG] RabbitMQ: Attempting to delete migrated queue %s@%s", name, vhost)
+
+	resp, err := rmqc.DeleteQueue(vhost, name, rabbithole.QueueDeleteOptions{IfEmpty: true})
+	log.Printf("[DEBUG] RabbitMQ: Queue delete response: %#v", resp)
+	if err != nil {
+		return m.abort(rmqc, vhost, name, temporary, bindings, err)
+	}
+
+	if err := declareQueue(rmqc, vhost, name, settingsMap); err != nil {
+		if previousErr := declareQueue(rmqc, vhost, name, previous); previousErr != nil {
+			return fmt.Errorf("Error declaring migrated RabbitMQ queue %s (%s) and declaring it again with its previous settings, its messages and bindings are held by the temporary queue %s: %w", name, err, temporary, previousErr)
+		}
+		m.step("Declared queue %s with its previous settings", name)
+		return m.abort(rmqc, vhost, name, temporary, bindings, err)
+	}
+	m.step("Declared queue %s with its new settings", name)
+
+	d.Partial(false)
+
+	if err := moveQueueBindings(rmqc, vhost, bindings, temporary, name); err != nil {
+		return m.abort(rmqc, vhost, name, temporary, bindings, err)
+	}
+	m.step("Moved %d bindings from queue %s back to %s", len(bindings), temporary, name)
+
+	moved, err = moveQueueMessages(rmqc, vhost, temporary, name, deadline)
+	if err != nil {
+		return fmt.Errorf("Queue %s was recreated but not all messages were moved back from the temporary queue %s, the shovel %s keeps moving them: %w", name, temporary, migrationShovelName(temporary), err)
+	}
+	m.step("Moved %d messages from queue %s back to %s", moved, temporary, name)
+
+	log.Printf("[DEBUG] RabbitMQ: Attempting to delete temporary queue %s@%s", temporary, vhost)
+
+	resp, err = rmqc.DeleteQueue(vhost, temporary, rabbithole.QueueDeleteOptions{IfEmpty: true})
+	log.Printf("[DEBUG] RabbitMQ: Queue delete response: %#v", resp)
+	if err != nil {
+		return fmt.Errorf("Error deleting temporary RabbitMQ queue %s: %w", temporary, err)
+	}
+	m.step("Deleted temporary queue %s", temporary)
+
+	return nil
+}
+
+// abort gives the bindings back to the original queue, and deletes the
+// temporary queue when it is empty or starts shovelling its messages back.
+func (m *queueMigration) abort(rmqc *rabbithole.Client, vhost string, name string, temporary string, bindings []rabbithole.BindingInfo, cause error) error {
+	log.Printf("[WARN] RabbitMQ: Aborting migration of queue %s@%s: %s", name, vhost, cause)
+
+	if err := moveQueueBindings(rmqc, vhost, bindings, temporary, name); err != nil {
+		return fmt.Errorf("Migration of queue %s aborted (%s) and its bindings could not be restored from the temporary queue %s: %w", name, cause, temporary, err)
+	}
+	m.step("Restored %d bindings of queue %s", len(bindings), name)
+
+	resp, err := rmqc.DeleteQueue(vhost, temporary, rabbithole.QueueDeleteOptions{IfEmpty: true})
+	log.Printf("[DEBUG] RabbitMQ: Queue delete response: %#v", resp)
+	if err == nil {
+		m.step("Deleted temporary queue %s", temporary)
+		return fmt.Errorf("Migration of queue %s aborted, its bindings were restored: %w", name, cause)
+	}
+
+	if err := declareMigrationShovel(rmqc, vhost, temporary, name); err != nil {
+		return fmt.Errorf("Migration of queue %s aborted (%s) and the messages held by the temporary queue %s could not be moved back: %w", name, cause, temporary, err)
+	}
+	m.step("Moving the messages of temporary queue %s back to %s", temporary, name)
+
+	return fmt.Errorf("Migration of queue %s aborted, its bindings were restored and the messages are being moved back from the temporary queue %s: %w", name, temporary, cause)
+}
+
+// listQueueSourceBindings lists the bindings of a queue,
+// without the implicit binding to the default exchange.
+func listQueueSourceBindings(rmqc *rabbithole.Client, vhost string, name string) ([]rabbithole.BindingInfo, error) {
+	bindings, err := rmqc.ListQueueBindings(vhost, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []rabbithole.BindingInfo
+	for _, binding := range bindings {
+		if binding.Source == "" {
+			continue
+		}
+		result = append(result, binding)
+	}
+
+	return result, nil
+}
+
+// moveQueueBindings declares bindings on the queue to before removing them
+// from the queue from, so that no message is left unrouted in between.
+func moveQueueBindings(rmqc *rabbithole.Client, vhost string, bindings []rabbithole.BindingInfo, from string, to string) error {
+	for _, binding := range bindings {
+		target := rabbithole.BindingInfo{
+			Source:          binding.Source,
+			Destination:     to,
+			DestinationType: "queue",
+			RoutingKey:      binding.RoutingKey,
+			Arguments:       binding.Arguments,
+		}
+		if _, err := declareBinding(rmqc, vhost, target); err != nil {
+			return err
+		}
+
+		previous := rabbithole.BindingInfo{
+			Source:          binding.Source,
+			Destination:     from,
+			DestinationType: "queue",
+			PropertiesKey:   binding.PropertiesKey,
+		}
+		// The binding is missing when a previous, partial move already moved it.
+		resp, err := rmqc.DeleteBinding(vhost, previous)
+		log.Printf("[DEBUG] RabbitMQ: Binding delete response: %#v", resp)
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// moveQueueMessages shovels the messages of the queue source to the queue
+// destination until source is empty or the deadline is reached, and returns
+// the number of messages source had. The shovel keeps running on timeout.
+func moveQueueMessages(rmqc *rabbithole.Client, vhost string, source string, destination string, deadline time.Time) (int, error) {
+	shovelName := migrationShovelName(source)
+	moved := -1
+
+	for {
+		queue, err := rmqc.GetQueue(vhost, source)
+		if err != nil {
+			return 0, err
+		}
+		if moved < 0 {
+			moved = queue.Messages
+		}
+
+		running := true
+		if _, err := rmqc.GetShovel(vhost, shovelName); err != nil {
+			if !isNotFound(err) {
+				return 0, err
+			}
+			running = false
+		}
+
+		if !running && queue.Messages == 0 {
+			return moved, nil
+		}
+
+		log.Printf("[INFO] RabbitMQ: Moving messages from queue %s to %s in vhost %s, %d messages left", source, destination, vhost, queue.Messages)
+
+		// The shovel is left running, the caller decides whether to stop it.
+		if time.Now().After(deadline) {
+			return 0, fmt.Errorf("timed out moving messages from queue %s to %s, %d messages left", source, destination, queue.Messages)
+		}
+
+		if !running {
+			if err := declareMigrationShovel(rmqc, vhost, source, destination); err != nil {
+				return 0, err
+			}
+		}
+
+		time.Sleep(queueMigrationPollInterval)
+	}
+}
+
+func declareMigrationShovel(rmqc *rabbithole.Client, vhost string, source string, destination string) error {
+	// An URI without host connects the shovel to the local node.
+	uri := fmt.Sprintf("amqp:///%s", url.PathEscape(vhost))
+
+	shovelDefinition := rabbithole.ShovelDefinition{
+		AckMode:             "on-confirm",
+		SourceURI:           rabbithole.URISet{uri},
+		SourceProtocol:      "amqp091",
+		SourceQueue:         source,
+		SourceDeleteAfter:   "queue-length",
+		DestinationURI:      rabbithole.URISet{uri},
+		DestinationProtocol: "amqp091",
+		DestinationQueue:    destination,
+	}
+
+	shovelName := migrationShovelName(source)
+
+	log.Printf("[DEBUG] RabbitMQ: Attempting to declare shovel %s in vhost %s", shovelName, vhost)
+	resp, err := rmqc.DeclareShovel(vhost, shovelName, shovelDefinition)
+	log.Printf("[DEBUG] RabbitMQ: shovel declaration response: %#v", resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("Error declaring RabbitMQ shovel: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// deleteMigrationShovel stops the shovel moving the messages of source,
+// when it is still running.
+func deleteMigrationShovel(rmqc *rabbithole.Client, vhost string, source string) error {
+	resp, err := rmqc.DeleteShovel(vhost, migrationShovelName(source))
+	log.Printf("[DEBUG] RabbitMQ: shovel deletion response: %#v", resp)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// checkQueueSettings checks that the broker accepts settings for the queue
+// name by declaring them on a throwaway queue, before the queue is deleted.
+func checkQueueSettings(rmqc *rabbithole.Client, vhost string, name string, settingsMap map[string]interface{}) error {
+	throwaway := fmt.Sprintf("%s.terraform-check", name)
+
+	if err := declareQueue(rmqc, vhost, throwaway, settingsMap); err != nil {
+		return fmt.Errorf("The new settings of queue %s were rejected, the queue was not changed: %w", name, err)
+	}
+
+	resp, err := rmqc.DeleteQueue(vhost, throwaway)
+	log.Printf("[DEBUG] RabbitMQ: Queue delete response: %#v", resp)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("Error deleting RabbitMQ queue %s, declared to check the new settings of queue %s: %w", throwaway, name, err)
+	}
+
+	return nil
+}
+
+func migrationShovelName(source string) string {
+	return fmt.Sprintf("terraform-migration-%s", source)
+}
@@ -1,10 +1,12 @@
 package rabbitmq
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/url"
+	"reflect"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -20,73 +22,86 @@ func resourceBinding() *schema.Resource {
 		Delete:      DeleteBinding,
 		Description: "The `rabbitmq_binding` resource creates and manages a binding relationship between a queue an exchange.",
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importBinding,
 		},
 
-		Schema: map[string]*schema.Schema{
-			"source": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The source exchange.",
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceBindingV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceBindingStateUpgradeV0,
 			},
+		},
 
-			"vhost": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The vhost to create the resource in.",
-			},
+		Schema: bindingSchema(),
+	}
+}
 
-			"destination": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The destination queue or exchange.",
-			},
+func bindingSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"source": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The source exchange.",
+		},
 
-			"destination_type": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The type of the destination. Must be either `queue` or `exchange`.",
-			},
+		"vhost": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The vhost to create the resource in.",
+		},
 
-			"properties_key": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "A unique key to refer to the binding.",
-			},
+		"destination": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The destination queue or exchange.",
+		},
 
-			"routing_key": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Description: "A routing key for the binding.",
-			},
+		"destination_type": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The type of the destination. Must be either `queue` or `exchange`.",
+		},
 
-			"arguments": {
-				Type:          schema.TypeMap,
-				Optional:      true,
-				ForceNew:      true,
-				ConflictsWith: []string{"arguments_json"},
-				Description:   "Additional key/value arguments for the binding. Conflicts with `arguments_json`",
-			},
-			"arguments_json": {
-				Type:             schema.TypeString,
-				Optional:         true,
-				ForceNew:         true,
-				ValidateFunc:     validation.StringIsJSON,
-				ConflictsWith:    []string{"arguments"},
-				DiffSuppressFunc: structure.SuppressJsonDiff,
-				Description:      "Additional key/value arguments for the binding in JSON format. Conflicts with `arguments`",
-			},
+		"properties_key": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "A unique key to refer to the binding.",
+		},
+
+		"routing_key": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+			Description: "A routing key for the binding.",
+		},
+
+		"arguments": {
+			Type:          schema.TypeMap,
+			Optional:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{"arguments_json"},
+			Description:   "Additional key/value arguments for the binding. Conflicts with `arguments_json`",
+		},
+		"arguments_json": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ForceNew:         true,
+			ValidateFunc:     validation.StringIsJSON,
+			ConflictsWith:    []string{"arguments"},
+			DiffSuppressFunc: structure.SuppressJsonDiff,
+			Description:      "Additional key/value arguments for the binding in JSON format. Conflicts with `arguments`",
 		},
 	}
 }
 
 func CreateBinding(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	vhost := d.Get("vhost").(string)
 	arguments := d.Get("arguments").(map[string]interface{})
@@ -118,32 +133,28 @@ func CreateBinding(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[DEBUG] RabbitMQ: Binding properties key: %s", propertiesKey)
 	bindingInfo.PropertiesKey = propertiesKey
-	name := fmt.Sprintf("%s/%s/%s/%s/%s", percentEncodeSlashes(vhost), bindingInfo.Source, bindingInfo.Destination, bindingInfo.DestinationType, bindingInfo.PropertiesKey)
-	d.SetId(name)
+	d.SetId(bindingId(vhost, bindingInfo))
 
 	return ReadBinding(d, meta)
 }
 
 func ReadBinding(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	log.Printf("[TRACE] RabbitMQ: read binding resource ID (pre-split): %s", d.Id())
-	bindingId := strings.Split(d.Id(), "/")
-	log.Printf("[DEBUG] RabbitMQ: binding ID: %#v", bindingId)
-	if len(bindingId) < 5 {
-		return fmt.Errorf("Unable to determine binding ID")
+	vhost, bindingInfo, err := parseBindingId(d.Id())
+	if err != nil {
+		return err
 	}
 
-	vhost := percentDecodeSlashes(bindingId[0])
-	source := bindingId[1]
-	destination := bindingId[2]
-	destinationType := bindingId[3]
-	propertiesKey := bindingId[4]
+	source := bindingInfo.Source
+	destination := bindingInfo.Destination
+	destinationType := bindingInfo.DestinationType
+	propertiesKey := bindingInfo.PropertiesKey
 	log.Printf("[DEBUG] RabbitMQ: Attempting to find binding for: vhost=%s source=%s destination=%s destinationType=%s propertiesKey=%s",
 		vhost, source, destination, destinationType, propertiesKey)
 
 	var bindings []rabbithole.BindingInfo
-	var err error
 	if destinationType == "queue" {
 		bindings, err = rmqc.ListQueueBindingsBetween(vhost, source, destination)
 		if err != nil {
@@ -198,29 +209,15 @@ func ReadBinding(d *schema.ResourceData, meta interface{}) error {
 }
 
 func DeleteBinding(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
-
-	bindingId := strings.Split(d.Id(), "/")
-	if len(bindingId) < 5 {
-		return fmt.Errorf("Unable to determine binding ID")
-	}
+	rmqc := meta.(*providerMeta).Client
 
-	vhost := percentDecodeSlashes(bindingId[0])
-	source := bindingId[1]
-	destination := bindingId[2]
-	destinationType := bindingId[3]
-	propertiesKey := bindingId[4]
-
-	bindingInfo := rabbithole.BindingInfo{
-		Vhost:           vhost,
-		Source:          source,
-		Destination:     destination,
-		DestinationType: destinationType,
-		PropertiesKey:   propertiesKey,
+	vhost, bindingInfo, err := parseBindingId(d.Id())
+	if err != nil {
+		return err
 	}
 
 	log.Printf("[DEBUG] RabbitMQ: Attempting to delete binding for: vhost=%s source=%s destination=%s destinationType=%s propertiesKey=%s",
-		vhost, source, destination, destinationType, propertiesKey)
+		vhost, bindingInfo.Source, bindingInfo.Destination, bindingInfo.DestinationType, bindingInfo.PropertiesKey)
 
 	resp, err := rmqc.DeleteBinding(vhost, bindingInfo)
 	if err != nil {
@@ -264,3 +261,126 @@ func declareBinding(rmqc *rabbithole.Client, vhost string, bindingInfo rabbithol
 
 	return propertiesKey, nil
 }
+
+// importBinding accepts the ID of a binding, or an ID where the properties
+// key is replaced by the routing key of the binding, optionally followed by
+// its arguments as JSON: vhost/source/destination/destination_type/routing_key[/arguments_json].
+// Components are percent-encoded as in binding IDs.
+func importBinding(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	rmqc := meta.(*providerMeta).Client
+
+	components := strings.Split(d.Id(), "/")
+	if len(components) != 5 && len(components) != 6 {
+		return nil, fmt.Errorf("Unable to import binding %s, expected vhost/source/destination/destination_type/properties_key or vhost/source/destination/destination_type/routing_key[/arguments_json]", d.Id())
+	}
+
+	for i, component := range components {
+		components[i] = percentDecodeSlashes(component)
+	}
+
+	vhost := components[0]
+	source := components[1]
+	destination := components[2]
+	destinationType := components[3]
+	key := components[4]
+
+	var bindings []rabbithole.BindingInfo
+	var err error
+	switch destinationType {
+	case "queue":
+		bindings, err = rmqc.ListQueueBindingsBetween(vhost, source, destination)
+	case "exchange":
+		bindings, err = rmqc.ListExchangeBindingsBetween(vhost, source, destination)
+	default:
+		return nil, fmt.Errorf("Unable to import binding %s, the destination type must be either queue or exchange, got: %s", d.Id(), destinationType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var arguments map[string]interface{}
+	if len(components) == 6 {
+		if err := json.Unmarshal([]byte(components[5]), &arguments); err != nil {
+			return nil, fmt.Errorf("Unable to import binding %s, could not decode arguments: %w", d.Id(), err)
+		}
+	} else {
+		for _, binding := range bindings {
+			if binding.PropertiesKey == key {
+				d.SetId(bindingId(vhost, binding))
+				return []*schema.ResourceData{d}, nil
+			}
+		}
+	}
+
+	var matches []rabbithole.BindingInfo
+	for _, binding := range bindings {
+		if binding.RoutingKey != key {
+			continue
+		}
+		if len(components) == 6 && !reflect.DeepEqual(normalizeBindingArguments(binding.Arguments), normalizeBindingArguments(arguments)) {
+			continue
+		}
+		matches = append(matches, binding)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("Unable to import binding %s, no binding from %s to %s %s in vhost %s has the properties key or routing key %s and the given arguments", d.Id(), source, destinationType, destination, vhost, key)
+	case 1:
+		d.SetId(bindingId(vhost, matches[0]))
+		return []*schema.ResourceData{d}, nil
+	default:
+		propertiesKeys := make([]string, len(matches))
+		for i, binding := range matches {
+			propertiesKeys[i] = binding.PropertiesKey
+		}
+		return nil, fmt.Errorf("Unable to import binding %s, %d bindings from %s to %s %s in vhost %s have the routing key %s, with the properties keys: %s. Add their arguments as JSON to the ID or use the properties key", d.Id(), len(matches), source, destinationType, destination, vhost, key, strings.Join(propertiesKeys, ", "))
+	}
+}
+
+// normalizeBindingArguments returns nil for bindings without arguments,
+// whether their arguments are nil or empty.
+func normalizeBindingArguments(arguments map[string]interface{}) map[string]interface{} {
+	if len(arguments) == 0 {
+		return nil
+	}
+	return arguments
+}
+
+// resourceBindingV0 is the binding resource before all the components of its
+// ID were percent-encoded. Only the vhost was, so that names containing
+// slashes led to IDs that couldn't be split back.
+func resourceBindingV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: bindingSchema(),
+	}
+}
+
+// resourceBindingStateUpgradeV0 rewrites the ID of a binding from its attributes.
+func resourceBindingStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+
+	vhost, _ := rawState["vhost"].(string)
+	binding := rabbithole.BindingInfo{}
+	binding.Source, _ = rawState["source"].(string)
+	binding.Destination, _ = rawState["destination"].(string)
+	binding.DestinationType, _ = rawState["destination_type"].(string)
+	binding.PropertiesKey, _ = rawState["properties_key"].(string)
+
+	// The properties key is URL-encoded by RabbitMQ, so it is always the last
+	// component of a version 0 ID.
+	if binding.PropertiesKey == "" {
+		if id, ok := rawState["id"].(string); ok {
+			components := strings.Split(id, "/")
+			binding.PropertiesKey = components[len(components)-1]
+		}
+	}
+
+	id := bindingId(vhost, binding)
+	log.Printf("[DEBUG] RabbitMQ: Upgrading binding ID %v to %s", rawState["id"], id)
+	rawState["id"] = id
+
+	return rawState, nil
+}
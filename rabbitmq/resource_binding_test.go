@@ -1,10 +1,10 @@
 package rabbitmq
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
-	"strings"
 	"testing"
 
 	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
@@ -72,6 +72,28 @@ func TestAccBinding_slashEscaping(t *testing.T) {
 	})
 }
 
+func TestAccBinding_encodedNames(t *testing.T) {
+	var bindingInfo rabbithole.BindingInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccBindingCheckDestroy(bindingInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBindingConfig_encodedNames,
+				Check: testAccBindingCheck(
+					"rabbitmq_binding.test", &bindingInfo,
+				),
+			},
+			{
+				ResourceName:      "rabbitmq_binding.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func TestAccBinding_propertiesKey(t *testing.T) {
 	var bindingInfo rabbithole.BindingInfo
 	resource.Test(t, resource.TestCase{
@@ -89,6 +111,32 @@ func TestAccBinding_propertiesKey(t *testing.T) {
 	})
 }
 
+func TestResourceBindingStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":               "%2Fvirtual%2Fhost/events/orders/ünïcødé/queue/orders%2Fnew~",
+		"vhost":            "/virtual/host",
+		"source":           "events/orders",
+		"destination":      "ünïcødé/queue",
+		"destination_type": "queue",
+		"properties_key":   "orders%2Fnew~",
+	}
+
+	actual, err := resourceBindingStateUpgradeV0(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error upgrading state: %s", err)
+	}
+
+	expected := "%2Fvirtual%2Fhost/events%2Forders/ünïcødé%2Fqueue/queue/orders%252Fnew~"
+	if actual["id"] != expected {
+		t.Fatalf("upgraded ID is %v, expected %s", actual["id"], expected)
+	}
+
+	vhost, binding, err := parseBindingId(expected)
+	if err != nil || vhost != "/virtual/host" || binding.Source != "events/orders" || binding.Destination != "ünïcødé/queue" || binding.PropertiesKey != "orders%2Fnew~" {
+		t.Fatalf("upgraded ID %s doesn't parse back to the binding", expected)
+	}
+}
+
 func testAccBindingCheck(rn string, bindingInfo *rabbithole.BindingInfo) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -101,16 +149,19 @@ func testAccBindingCheck(rn string, bindingInfo *rabbithole.BindingInfo) resourc
 			return fmt.Errorf("binding id not set")
 		}
 
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
-		bindingParts := strings.Split(rs.Primary.ID, "/")
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+		vhost, expected, err := parseBindingId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
 
-		bindings, err := rmqc.ListBindingsIn(percentDecodeSlashes(bindingParts[0]))
+		bindings, err := rmqc.ListBindingsIn(vhost)
 		if err != nil {
 			return fmt.Errorf("Error retrieving exchange: %s", err)
 		}
 
 		for _, binding := range bindings {
-			if binding.Source == bindingParts[1] && binding.Destination == bindingParts[2] && binding.DestinationType == bindingParts[3] && binding.PropertiesKey == bindingParts[4] {
+			if binding.Source == expected.Source && binding.Destination == expected.Destination && binding.DestinationType == expected.DestinationType && binding.PropertiesKey == expected.PropertiesKey {
 				*bindingInfo = binding
 				return nil
 			}
@@ -148,7 +199,7 @@ func testAccBindingCheckJsonArguments(rn string, bindingInfo *rabbithole.Binding
 
 func testAccBindingCheckDestroy(bindingInfo rabbithole.BindingInfo) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 
 		bindings, err := rmqc.ListBindingsIn(bindingInfo.Vhost)
 		if err != nil {
@@ -334,6 +385,54 @@ resource "rabbitmq_binding" "test" {
 }
 `
 
+const testAccBindingConfig_encodedNames = `
+resource "rabbitmq_vhost" "test" {
+    name = "/virtual//host///"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user = "guest"
+    vhost = "${rabbitmq_vhost.test.name}"
+    permissions {
+        configure = ".*"
+        write = ".*"
+        read = ".*"
+    }
+}
+
+resource "rabbitmq_exchange" "test" {
+    name = "events/ünïcødé"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        type = "topic"
+        durable = true
+        auto_delete = false
+    }
+}
+
+resource "rabbitmq_queue" "test" {
+    name = "orders/%2F/🐇"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        durable = true
+        auto_delete = false
+    }
+}
+
+resource "rabbitmq_binding" "test" {
+    source = "${rabbitmq_exchange.test.name}"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    destination = "${rabbitmq_queue.test.name}"
+    destination_type = "queue"
+    routing_key = "///routing//key/"
+    arguments = {
+      key1 = "value1"
+      key2 = "value2"
+      key3 = "value3"
+    }
+}
+`
+
 const testAccBindingConfig_propertiesKey = `
 resource "rabbitmq_vhost" "test" {
     name = "test"
@@ -1,20 +1,31 @@
 package rabbitmq
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 
 	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceExchange() *schema.Resource {
 	return &schema.Resource{
-		Create:      CreateExchange,
-		Read:        ReadExchange,
-		Delete:      DeleteExchange,
-		Description: "The `rabbitmq_exchange` resource creates and manages an exchange in a RabbitMQ server.",
+		Create:        CreateExchange,
+		Read:          ReadExchange,
+		Delete:        DeleteExchange,
+		CustomizeDiff: customizeExchangeDiff,
+		Description:   "The `rabbitmq_exchange` resource creates and manages an exchange in a RabbitMQ server.",
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -46,7 +57,7 @@ func resourceExchange() *schema.Resource {
 						"type": {
 							Type:        schema.TypeString,
 							Required:    true,
-							Description: "The type of the exchange.",
+							Description: "The type of the exchange. It is checked against the exchange types enabled on the server.",
 						},
 
 						"durable": {
@@ -63,10 +74,33 @@ func resourceExchange() *schema.Resource {
 							Description: "Whether the exchange is auto-deleted when no longer in use.",
 						},
 
-						"arguments": {
-							Type:        schema.TypeMap,
+						"internal": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether the exchange is internal. Clients can't publish to an internal exchange, only other exchanges bound to it can route messages to it.",
+						},
+
+						"alternate_exchange": {
+							Type:        schema.TypeString,
 							Optional:    true,
-							Description: "Additional key/value settings for the exchange.",
+							Description: "The exchange to which messages that can't be routed are republished. It must exist in the same vhost. Sets the `alternate-exchange` argument.",
+						},
+
+						"arguments": {
+							Type:          schema.TypeMap,
+							Optional:      true,
+							ConflictsWith: []string{"settings.0.arguments_json"},
+							Description:   "Additional key/value settings for the exchange. All values will be sent to RabbitMQ as a string. If you require non-string values, use `arguments_json`.",
+						},
+
+						"arguments_json": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateFunc:     validation.StringIsJSON,
+							ConflictsWith:    []string{"settings.0.arguments"},
+							DiffSuppressFunc: structure.SuppressJsonDiff,
+							Description:      "A nested JSON string which contains additional settings for the exchange. This is useful for when the arguments contain non-string values.",
 						},
 					},
 				},
@@ -75,8 +109,81 @@ func resourceExchange() *schema.Resource {
 	}
 }
 
+// exchangeTypePlugins maps the exchange types provided by plugins
+// to the plugin providing them.
+var exchangeTypePlugins = map[string]string{
+	"x-consistent-hash":       "rabbitmq_consistent_hash_exchange",
+	"x-delayed-message":       "rabbitmq_delayed_message_exchange",
+	"x-jms-topic":             "rabbitmq_jms_topic_exchange",
+	"x-lvc":                   "rabbitmq_lvc_exchange",
+	"x-message-deduplication": "rabbitmq_message_deduplication",
+	"x-modulus-hash":          "rabbitmq_sharding",
+	"x-random":                "rabbitmq_random_exchange",
+	"x-recent-history":        "rabbitmq_recent_history_exchange",
+	"x-rtopic":                "rabbitmq_rtopic_exchange",
+}
+
+// customizeExchangeDiff checks the exchange type against the exchange types
+// enabled on the server, so that a wrong type fails at plan time, and warns
+// when the alternate exchange does not exist.
+func customizeExchangeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	if diff.HasChange("settings.0.type") && diff.NewValueKnown("settings.0.type") {
+		if err := checkExchangeType(rmqc, diff.Get("settings.0.type").(string)); err != nil {
+			return err
+		}
+	}
+
+	if diff.HasChange("settings.0.alternate_exchange") && diff.NewValueKnown("settings.0.alternate_exchange") && diff.NewValueKnown("vhost") {
+		vhost := diff.Get("vhost").(string)
+		alternate := diff.Get("settings.0.alternate_exchange").(string)
+		exists, err := exchangeExists(rmqc, vhost, alternate)
+		if err != nil {
+			return err
+		}
+		// The alternate exchange may be created by the same apply, before
+		// this exchange when it depends on it.
+		if !exists {
+			detail := fmt.Sprintf("The alternate exchange %s does not exist in vhost %s. Applying fails unless it is created first.", alternate, vhost)
+			if diff.Id() != "" {
+				detail = fmt.Sprintf("The alternate exchange %s does not exist in vhost %s. Changing it replaces the exchange, which is deleted before the new one fails to be created unless the alternate exchange is created first.", alternate, vhost)
+			}
+			addPlanWarning(ctx, diag.Diagnostic{
+				Summary: "Alternate exchange does not exist",
+				Detail:  detail,
+			})
+		}
+	}
+
+	return nil
+}
+
+func checkExchangeType(rmqc *rabbithole.Client, exchangeType string) error {
+	overview, err := rmqc.Overview()
+	if err != nil {
+		return fmt.Errorf("Error retrieving the exchange types supported by RabbitMQ: %w", err)
+	}
+
+	var available []string
+	for _, t := range overview.ExchangeTypes {
+		if t.Name == exchangeType {
+			return nil
+		}
+		available = append(available, t.Name)
+	}
+	sort.Strings(available)
+
+	message := fmt.Sprintf("Exchange type %s is not supported by the server, available types are: %s", exchangeType, strings.Join(available, ", "))
+	if plugin, ok := exchangeTypePlugins[exchangeType]; ok {
+		message += fmt.Sprintf(". The %s type is provided by the %s plugin, which is not enabled", exchangeType, plugin)
+	}
+
+	return errors.New(message)
+}
+
 func CreateExchange(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name := d.Get("name").(string)
 	vhost := d.Get("vhost").(string)
@@ -87,6 +194,23 @@ func CreateExchange(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Unable to parse settings")
 	}
 
+	// If arguments_json is used, unmarshal it into a generic interface
+	// and use it as the "arguments" key for the exchange.
+	if v, ok := settingsMap["arguments_json"].(string); ok && v != "" {
+		var arguments map[string]interface{}
+		err := json.Unmarshal([]byte(v), &arguments)
+		if err != nil {
+			return err
+		}
+
+		delete(settingsMap, "arguments_json")
+		settingsMap["arguments"] = arguments
+	}
+
+	if err := checkAlternateExchange(rmqc, vhost, settingsMap); err != nil {
+		return err
+	}
+
 	if err := declareExchange(rmqc, vhost, name, settingsMap); err != nil {
 		return err
 	}
@@ -98,7 +222,7 @@ func CreateExchange(d *schema.ResourceData, meta interface{}) error {
 }
 
 func ReadExchange(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -120,7 +244,31 @@ func ReadExchange(d *schema.ResourceData, meta interface{}) error {
 	e["type"] = exchangeSettings.Type
 	e["durable"] = exchangeSettings.Durable
 	e["auto_delete"] = exchangeSettings.AutoDelete
-	e["arguments"] = exchangeSettings.Arguments
+	e["internal"] = exchangeSettings.Internal
+
+	// The alternate exchange stays in the arguments if it is configured there.
+	arguments := make(map[string]interface{}, len(exchangeSettings.Arguments))
+	for key, value := range exchangeSettings.Arguments {
+		arguments[key] = value
+	}
+	if _, ok := configuredArguments(d)["alternate-exchange"]; !ok {
+		if v, ok := arguments["alternate-exchange"].(string); ok {
+			e["alternate_exchange"] = v
+			delete(arguments, "alternate-exchange")
+		}
+	}
+
+	// As with queues, the user may have used either `arguments` or `arguments_json`,
+	// and that choice is preserved unless the arguments contain non-string values.
+	if _, ok := d.GetOk("settings.0.arguments_json"); ok || nonStringInArguments(arguments) {
+		bytes, err := json.Marshal(arguments)
+		if err != nil {
+			return err
+		}
+		e["arguments_json"] = string(bytes)
+	} else {
+		e["arguments"] = arguments
+	}
 	exchange[0] = e
 	d.Set("settings", exchange)
 
@@ -128,7 +276,7 @@ func ReadExchange(d *schema.ResourceData, meta interface{}) error {
 }
 
 func DeleteExchange(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -170,13 +318,38 @@ func declareExchange(rmqc *rabbithole.Client, vhost string, name string, setting
 		exchangeSettings.AutoDelete = v
 	}
 
+	arguments := make(map[string]interface{})
 	if v, ok := settingsMap["arguments"].(map[string]interface{}); ok {
-		exchangeSettings.Arguments = v
+		for key, value := range v {
+			arguments[key] = value
+		}
+	}
+
+	if v, ok := settingsMap["alternate_exchange"].(string); ok && v != "" {
+		if _, ok := arguments["alternate-exchange"]; ok {
+			return fmt.Errorf("`alternate_exchange` conflicts with the `alternate-exchange` key of the exchange arguments")
+		}
+		arguments["alternate-exchange"] = v
+	}
+
+	if len(arguments) > 0 {
+		exchangeSettings.Arguments = arguments
 	}
 
 	log.Printf("[DEBUG] RabbitMQ: Attempting to declare exchange %s@%s: %#v", name, vhost, exchangeSettings)
 
-	resp, err := rmqc.DeclareExchange(vhost, name, exchangeSettings)
+	var resp *http.Response
+	var err error
+	if internal, ok := settingsMap["internal"].(bool); ok && internal {
+		// rabbithole.ExchangeSettings has no internal field.
+		declaration := struct {
+			rabbithole.ExchangeSettings
+			Internal bool `json:"internal"`
+		}{exchangeSettings, true}
+		resp, err = managementRequest(rmqc, http.MethodPut, "exchanges/"+url.PathEscape(vhost)+"/"+url.PathEscape(name), declaration, nil)
+	} else {
+		resp, err = rmqc.DeclareExchange(vhost, name, exchangeSettings)
+	}
 	log.Printf("[DEBUG] RabbitMQ: Exchange declare response: %#v", resp)
 	if err != nil {
 		return err
@@ -188,3 +361,37 @@ func declareExchange(rmqc *rabbithole.Client, vhost string, name string, setting
 
 	return nil
 }
+
+// checkAlternateExchange checks that the alternate exchange of the settings,
+// if any, exists in vhost.
+func checkAlternateExchange(rmqc *rabbithole.Client, vhost string, settingsMap map[string]interface{}) error {
+	alternate, ok := settingsMap["alternate_exchange"].(string)
+	if !ok || alternate == "" {
+		return nil
+	}
+
+	exists, err := exchangeExists(rmqc, vhost, alternate)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("The alternate exchange %s does not exist in vhost %s", alternate, vhost)
+	}
+
+	return nil
+}
+
+func exchangeExists(rmqc *rabbithole.Client, vhost string, name string) (bool, error) {
+	if name == "" {
+		return true, nil
+	}
+
+	if _, err := rmqc.GetExchange(vhost, name); err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,369 @@
+package rabbitmq
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+)
+
+// rabbitmq_exchange_bindings owns all the bindings whose source is an exchange.
+// Bindings are matched on their destination, routing key and arguments, which
+// together identify a binding in RabbitMQ.
+
+func resourceExchangeBindings() *schema.Resource {
+	return &schema.Resource{
+		Create:      CreateExchangeBindings,
+		Read:        ReadExchangeBindings,
+		Update:      UpdateExchangeBindings,
+		Delete:      DeleteExchangeBindings,
+		Description: "The `rabbitmq_exchange_bindings` resource manages the full set of bindings whose source is an exchange. Bindings of the exchange that are not in the configuration are removed.",
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"exchange": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The source exchange of the bindings.",
+			},
+
+			"vhost": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "/",
+				ForceNew:    true,
+				Description: "The vhost of the exchange.",
+			},
+
+			"binding": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "A binding of the exchange.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"destination": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The destination queue or exchange.",
+						},
+
+						"destination_type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"queue", "exchange"}, false),
+							Description:  "The type of the destination. Must be either `queue` or `exchange`.",
+						},
+
+						"routing_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "A routing key for the binding.",
+						},
+
+						"arguments": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Additional key/value arguments for the binding. Only one of `arguments` and `arguments_json` can be set.",
+						},
+
+						"arguments_json": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringIsJSON,
+							Description:  "Additional key/value arguments for the binding in JSON format. Only one of `arguments` and `arguments_json` can be set.",
+						},
+					},
+				},
+			},
+
+			"ignore_destination_patterns": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsValidRegExp,
+				},
+				Description: "Regular expressions matching the destinations of bindings that are left alone, e.g. `^amq\\.gen-` for the server-named queues of clients. These bindings are neither read nor removed.",
+			},
+		},
+	}
+}
+
+func CreateExchangeBindings(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	exchange := d.Get("exchange").(string)
+	vhost := d.Get("vhost").(string)
+
+	if err := syncExchangeBindings(d, rmqc, vhost, exchange); err != nil {
+		return err
+	}
+
+	id := fmt.Sprintf("%s@%s", exchange, vhost)
+	d.SetId(id)
+
+	return ReadExchangeBindings(d, meta)
+}
+
+func ReadExchangeBindings(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	exchange, vhost, err := parseResourceId(d)
+	if err != nil {
+		return err
+	}
+
+	if _, err := rmqc.GetExchange(vhost, exchange); err != nil {
+		return checkDeleted(d, err)
+	}
+
+	bindings, err := listExchangeBindings(d, rmqc, vhost, exchange)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: Bindings retrieved for exchange %s: %#v", d.Id(), bindings)
+
+	// Bindings already in state keep the attribute their arguments were set with.
+	previous := make(map[string]interface{})
+	for _, v := range d.Get("binding").(*schema.Set).List() {
+		key, err := exchangeBindingKey(v.(map[string]interface{}))
+		if err != nil {
+			return err
+		}
+		previous[key] = v
+	}
+
+	result := make([]interface{}, 0, len(bindings))
+	for key, binding := range bindings {
+		if v, ok := previous[key]; ok {
+			result = append(result, v)
+			continue
+		}
+
+		b := map[string]interface{}{
+			"destination":      binding.Destination,
+			"destination_type": binding.DestinationType,
+			"routing_key":      binding.RoutingKey,
+		}
+		if nonStringInArguments(binding.Arguments) {
+			bytes, err := json.Marshal(binding.Arguments)
+			if err != nil {
+				return err
+			}
+			b["arguments_json"] = string(bytes)
+		} else if len(binding.Arguments) > 0 {
+			b["arguments"] = binding.Arguments
+		}
+		result = append(result, b)
+	}
+
+	d.Set("exchange", exchange)
+	d.Set("vhost", vhost)
+
+	return d.Set("binding", result)
+}
+
+func UpdateExchangeBindings(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	exchange, vhost, err := parseResourceId(d)
+	if err != nil {
+		return err
+	}
+
+	if err := syncExchangeBindings(d, rmqc, vhost, exchange); err != nil {
+		return err
+	}
+
+	return ReadExchangeBindings(d, meta)
+}
+
+func DeleteExchangeBindings(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	exchange, vhost, err := parseResourceId(d)
+	if err != nil {
+		return err
+	}
+
+	bindings, err := listExchangeBindings(d, rmqc, vhost, exchange)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, binding := range bindings {
+		if err := deleteExchangeBinding(rmqc, vhost, binding); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncExchangeBindings declares the configured bindings of the exchange
+// and removes the others.
+func syncExchangeBindings(d *schema.ResourceData, rmqc *rabbithole.Client, vhost string, exchange string) error {
+	current, err := listExchangeBindings(d, rmqc, vhost, exchange)
+	if err != nil {
+		return err
+	}
+
+	desired := make(map[string]rabbithole.BindingInfo)
+	for _, v := range d.Get("binding").(*schema.Set).List() {
+		b := v.(map[string]interface{})
+		key, err := exchangeBindingKey(b)
+		if err != nil {
+			return err
+		}
+
+		arguments, err := exchangeBindingArguments(b)
+		if err != nil {
+			return err
+		}
+
+		desired[key] = rabbithole.BindingInfo{
+			Source:          exchange,
+			Destination:     b["destination"].(string),
+			DestinationType: b["destination_type"].(string),
+			RoutingKey:      b["routing_key"].(string),
+			Arguments:       arguments,
+		}
+	}
+
+	// Bindings are declared before the others are removed,
+	// so that messages keep being routed in between.
+	for key, binding := range desired {
+		if _, ok := current[key]; ok {
+			continue
+		}
+		if _, err := declareBinding(rmqc, vhost, binding); err != nil {
+			return err
+		}
+	}
+
+	for key, binding := range current {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		if err := deleteExchangeBinding(rmqc, vhost, binding); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listExchangeBindings returns the bindings whose source is the exchange,
+// except the ones with an ignored destination, by binding key.
+func listExchangeBindings(d *schema.ResourceData, rmqc *rabbithole.Client, vhost string, exchange string) (map[string]rabbithole.BindingInfo, error) {
+	var ignored []*regexp.Regexp
+	for _, v := range d.Get("ignore_destination_patterns").([]interface{}) {
+		pattern, err := regexp.Compile(v.(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore_destination_patterns %q: %w", v, err)
+		}
+		ignored = append(ignored, pattern)
+	}
+
+	bindings, err := rmqc.ListExchangeBindingsWithSource(vhost, exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]rabbithole.BindingInfo, len(bindings))
+	for _, binding := range bindings {
+		if exchangeBindingIgnored(binding.Destination, ignored) {
+			log.Printf("[DEBUG] RabbitMQ: Ignoring binding of exchange %s to %s %s", exchange, binding.DestinationType, binding.Destination)
+			continue
+		}
+
+		key, err := bindingInfoKey(binding.Destination, binding.DestinationType, binding.RoutingKey, binding.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = binding
+	}
+
+	return result, nil
+}
+
+func exchangeBindingIgnored(destination string, ignored []*regexp.Regexp) bool {
+	for _, pattern := range ignored {
+		if pattern.MatchString(destination) {
+			return true
+		}
+	}
+	return false
+}
+
+// exchangeBindingArguments returns the arguments of a configured binding.
+func exchangeBindingArguments(b map[string]interface{}) (map[string]interface{}, error) {
+	arguments, _ := b["arguments"].(map[string]interface{})
+
+	if v, ok := b["arguments_json"].(string); ok && v != "" {
+		if len(arguments) > 0 {
+			return nil, fmt.Errorf("Only one of `arguments` and `arguments_json` can be set on the binding to %s %s", b["destination_type"], b["destination"])
+		}
+
+		if err := json.Unmarshal([]byte(v), &arguments); err != nil {
+			return nil, err
+		}
+	}
+
+	return arguments, nil
+}
+
+// exchangeBindingKey returns the key identifying a configured binding.
+func exchangeBindingKey(b map[string]interface{}) (string, error) {
+	arguments, err := exchangeBindingArguments(b)
+	if err != nil {
+		return "", err
+	}
+
+	return bindingInfoKey(b["destination"].(string), b["destination_type"].(string), b["routing_key"].(string), arguments)
+}
+
+// bindingInfoKey returns a key identifying a binding of an exchange,
+// independent of how its arguments are written.
+func bindingInfoKey(destination string, destinationType string, routingKey string, arguments map[string]interface{}) (string, error) {
+	encodedArguments := ""
+	if len(arguments) > 0 {
+		bytes, err := json.Marshal(arguments)
+		if err != nil {
+			return "", err
+		}
+		encodedArguments = string(bytes)
+	}
+
+	key, err := json.Marshal([]string{destination, destinationType, routingKey, encodedArguments})
+	return string(key), err
+}
+
+func deleteExchangeBinding(rmqc *rabbithole.Client, vhost string, binding rabbithole.BindingInfo) error {
+	log.Printf("[DEBUG] RabbitMQ: Attempting to delete binding for: vhost=%s source=%s destination=%s destinationType=%s propertiesKey=%s",
+		vhost, binding.Source, binding.Destination, binding.DestinationType, binding.PropertiesKey)
+
+	resp, err := rmqc.DeleteBinding(vhost, binding)
+	log.Printf("[DEBUG] RabbitMQ: Binding delete response: %#v", resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 && resp.StatusCode != 404 {
+		return fmt.Errorf("Error deleting RabbitMQ binding: %s", resp.Status)
+	}
+
+	return nil
+}
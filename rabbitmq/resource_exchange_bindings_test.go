@@ -0,0 +1,209 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccExchangeBindings_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccExchangeBindingsCheckDestroy("test", "events"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccExchangeBindingsConfig(testAccExchangeBindingsConfig_twoBindings),
+				Check: resource.ComposeTestCheckFunc(
+					testAccExchangeBindingsCheck("rabbitmq_exchange_bindings.test", []string{
+						"exchange:audit:#",
+						"queue:orders:orders.*",
+					}),
+					resource.TestCheckResourceAttr("rabbitmq_exchange_bindings.test", "binding.#", "2"),
+				),
+			},
+			{
+				// Bindings added outside of Terraform are removed,
+				// except the ones with an ignored destination.
+				PreConfig: func() {
+					rmqc := testAccProvider.Meta().(*providerMeta).Client
+					for _, binding := range []rabbithole.BindingInfo{
+						{Source: "events", Destination: "orders", DestinationType: "queue", RoutingKey: "rogue"},
+						{Source: "events", Destination: "ignored.replies", DestinationType: "queue", RoutingKey: "replies"},
+					} {
+						if _, err := rmqc.DeclareBinding("test", binding); err != nil {
+							t.Fatalf("Error declaring binding: %s", err)
+						}
+					}
+				},
+				Config: testAccExchangeBindingsConfig(testAccExchangeBindingsConfig_twoBindings),
+				Check: resource.ComposeTestCheckFunc(
+					testAccExchangeBindingsCheck("rabbitmq_exchange_bindings.test", []string{
+						"exchange:audit:#",
+						"queue:ignored.replies:replies",
+						"queue:orders:orders.*",
+					}),
+					resource.TestCheckResourceAttr("rabbitmq_exchange_bindings.test", "binding.#", "2"),
+				),
+			},
+			{
+				Config: testAccExchangeBindingsConfig(testAccExchangeBindingsConfig_oneBinding),
+				Check: resource.ComposeTestCheckFunc(
+					testAccExchangeBindingsCheck("rabbitmq_exchange_bindings.test", []string{
+						"queue:ignored.replies:replies",
+						"queue:orders:orders.created",
+					}),
+					resource.TestCheckResourceAttr("rabbitmq_exchange_bindings.test", "binding.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+// testAccExchangeBindingsCheck checks the bindings of the exchange,
+// given as destination_type:destination:routing_key.
+func testAccExchangeBindingsCheck(rn string, expected []string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("exchange bindings id not set")
+		}
+
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+		exchange, vhost, err := parseId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		bindings, err := rmqc.ListExchangeBindingsWithSource(vhost, exchange)
+		if err != nil {
+			return fmt.Errorf("Error retrieving bindings: %s", err)
+		}
+
+		var actual []string
+		for _, binding := range bindings {
+			actual = append(actual, fmt.Sprintf("%s:%s:%s", binding.DestinationType, binding.Destination, binding.RoutingKey))
+		}
+		sort.Strings(actual)
+
+		if strings.Join(actual, ",") != strings.Join(expected, ",") {
+			return fmt.Errorf("Exchange %s has bindings %v, expected %v", rs.Primary.ID, actual, expected)
+		}
+
+		return nil
+	}
+}
+
+func testAccExchangeBindingsCheckDestroy(vhost string, exchange string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+
+		bindings, err := rmqc.ListExchangeBindingsWithSource(vhost, exchange)
+		if err != nil {
+			// The exchange was removed with its bindings.
+			return nil
+		}
+
+		for _, binding := range bindings {
+			if !strings.HasPrefix(binding.Destination, "ignored.") {
+				return fmt.Errorf("Binding of %s to %s still exist", exchange, binding.Destination)
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccExchangeBindingsConfig(bindings string) string {
+	return `
+resource "rabbitmq_vhost" "test" {
+    name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user = "guest"
+    vhost = "${rabbitmq_vhost.test.name}"
+    permissions {
+        configure = ".*"
+        write = ".*"
+        read = ".*"
+    }
+}
+
+resource "rabbitmq_exchange" "events" {
+    name = "events"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        type = "topic"
+        durable = true
+    }
+}
+
+resource "rabbitmq_exchange" "audit" {
+    name = "audit"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        type = "fanout"
+        durable = true
+    }
+}
+
+resource "rabbitmq_queue" "orders" {
+    name = "orders"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        durable = true
+    }
+}
+
+resource "rabbitmq_queue" "replies" {
+    name = "ignored.replies"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        durable = true
+    }
+}
+
+resource "rabbitmq_exchange_bindings" "test" {
+    exchange = "${rabbitmq_exchange.events.name}"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    ignore_destination_patterns = ["^ignored\\."]
+` + bindings + `
+    depends_on = [rabbitmq_queue.replies]
+}`
+}
+
+const testAccExchangeBindingsConfig_twoBindings = `
+    binding {
+        destination = "${rabbitmq_queue.orders.name}"
+        destination_type = "queue"
+        routing_key = "orders.*"
+    }
+
+    binding {
+        destination = "${rabbitmq_exchange.audit.name}"
+        destination_type = "exchange"
+        routing_key = "#"
+        arguments_json = jsonencode({
+            "x-priority" = 10
+        })
+    }
+`
+
+const testAccExchangeBindingsConfig_oneBinding = `
+    binding {
+        destination = "${rabbitmq_queue.orders.name}"
+        destination_type = "queue"
+        routing_key = "orders.created"
+    }
+`
@@ -2,6 +2,8 @@ package rabbitmq
 
 import (
 	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -28,6 +30,146 @@ func TestAccExchange(t *testing.T) {
 	})
 }
 
+func TestAccExchange_internalWithAlternateExchange(t *testing.T) {
+	var exchangeInfo rabbithole.ExchangeInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccExchangeCheckDestroy(&exchangeInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccExchangeConfig_internal,
+				Check: resource.ComposeTestCheckFunc(
+					testAccExchangeCheck("rabbitmq_exchange.test", &exchangeInfo),
+					testAccExchangeCheckSettings("rabbitmq_exchange.test", true, "unroutable"),
+					resource.TestCheckResourceAttr("rabbitmq_exchange.test", "settings.0.internal", "true"),
+					resource.TestCheckResourceAttr("rabbitmq_exchange.test", "settings.0.alternate_exchange", "unroutable"),
+					resource.TestCheckResourceAttr("rabbitmq_exchange.test", "settings.0.arguments.%", "0"),
+				),
+			},
+			{
+				// The new alternate exchange does not exist yet when planning,
+				// it is created by the same apply.
+				Config: testAccExchangeConfig_internalNewAlternate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccExchangeCheck("rabbitmq_exchange.test", &exchangeInfo),
+					testAccExchangeCheckSettings("rabbitmq_exchange.test", true, "unroutable-v2"),
+					resource.TestCheckResourceAttr("rabbitmq_exchange.test", "settings.0.alternate_exchange", "unroutable-v2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccExchange_argumentsJson(t *testing.T) {
+	var exchangeInfo rabbithole.ExchangeInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccExchangeCheckDestroy(&exchangeInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccExchangeConfig_argumentsJson,
+				Check: resource.ComposeTestCheckFunc(
+					testAccExchangeCheck("rabbitmq_exchange.test", &exchangeInfo),
+					testAccExchangeCheckArguments("rabbitmq_exchange.test", map[string]interface{}{
+						"hash-header": "customer-id",
+						"x-weight":    float64(10),
+						"x-flag":      true,
+					}),
+				),
+			},
+			{
+				ResourceName:      "rabbitmq_exchange.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccExchange_missingAlternateExchange(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccExchangeConfig_missingAlternate,
+				ExpectError: regexp.MustCompile("The alternate exchange missing does not exist in vhost test"),
+			},
+		},
+	})
+}
+
+func TestAccExchange_unsupportedType(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccExchangeConfig_type("topics"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Exchange type topics is not supported by the server, available types are: .*topic"),
+			},
+			{
+				Config:      testAccExchangeConfig_type("x-delayed-message"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("provided by the rabbitmq_delayed_message_exchange plugin, which is not enabled"),
+			},
+		},
+	})
+}
+
+func testAccExchangeCheckSettings(rn string, internal bool, alternate string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+		exchParts := strings.Split(rs.Primary.ID, "@")
+
+		exchange, err := rmqc.GetExchange(exchParts[1], exchParts[0])
+		if err != nil {
+			return fmt.Errorf("Error retrieving exchange: %s", err)
+		}
+
+		if exchange.Internal != internal {
+			return fmt.Errorf("Exchange %s internal is %t, expected %t", rs.Primary.ID, exchange.Internal, internal)
+		}
+
+		if v := exchange.Arguments["alternate-exchange"]; v != alternate {
+			return fmt.Errorf("Exchange %s alternate-exchange argument is %v, expected %s", rs.Primary.ID, v, alternate)
+		}
+
+		return nil
+	}
+}
+
+func testAccExchangeCheckArguments(rn string, expected map[string]interface{}) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+		exchParts := strings.Split(rs.Primary.ID, "@")
+
+		exchange, err := rmqc.GetExchange(exchParts[1], exchParts[0])
+		if err != nil {
+			return fmt.Errorf("Error retrieving exchange: %s", err)
+		}
+
+		if !reflect.DeepEqual(exchange.Arguments, expected) {
+			return fmt.Errorf("Exchange %s has arguments %#v, expected %#v", rs.Primary.ID, exchange.Arguments, expected)
+		}
+
+		return nil
+	}
+}
+
 func testAccExchangeCheck(rn string, exchangeInfo *rabbithole.ExchangeInfo) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -39,7 +181,7 @@ func testAccExchangeCheck(rn string, exchangeInfo *rabbithole.ExchangeInfo) reso
 			return fmt.Errorf("exchange id not set")
 		}
 
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		exchParts := strings.Split(rs.Primary.ID, "@")
 
 		exchanges, err := rmqc.ListExchangesIn(exchParts[1])
@@ -60,7 +202,7 @@ func testAccExchangeCheck(rn string, exchangeInfo *rabbithole.ExchangeInfo) reso
 
 func testAccExchangeCheckDestroy(exchangeInfo *rabbithole.ExchangeInfo) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 
 		exchanges, err := rmqc.ListExchangesIn(exchangeInfo.Vhost)
 		if err != nil {
@@ -101,3 +243,147 @@ resource "rabbitmq_exchange" "test" {
         auto_delete = true
     }
 }`
+
+const testAccExchangeConfig_internal = `
+resource "rabbitmq_vhost" "test" {
+    name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user = "guest"
+    vhost = "${rabbitmq_vhost.test.name}"
+    permissions {
+        configure = ".*"
+        write = ".*"
+        read = ".*"
+    }
+}
+
+resource "rabbitmq_exchange" "unroutable" {
+    name = "unroutable"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        type = "fanout"
+        durable = true
+    }
+}
+
+resource "rabbitmq_exchange" "test" {
+    name = "test"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        type = "topic"
+        durable = true
+        internal = true
+        alternate_exchange = "${rabbitmq_exchange.unroutable.name}"
+    }
+}`
+
+const testAccExchangeConfig_internalNewAlternate = `
+resource "rabbitmq_vhost" "test" {
+    name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user = "guest"
+    vhost = "${rabbitmq_vhost.test.name}"
+    permissions {
+        configure = ".*"
+        write = ".*"
+        read = ".*"
+    }
+}
+
+resource "rabbitmq_exchange" "unroutable" {
+    name = "unroutable"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        type = "fanout"
+        durable = true
+    }
+}
+
+resource "rabbitmq_exchange" "unroutable_v2" {
+    name = "unroutable-v2"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        type = "fanout"
+        durable = true
+    }
+}
+
+resource "rabbitmq_exchange" "test" {
+    name = "test"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        type = "topic"
+        durable = true
+        internal = true
+        alternate_exchange = "unroutable-v2"
+    }
+
+    depends_on = [rabbitmq_exchange.unroutable_v2]
+}`
+
+const testAccExchangeConfig_missingAlternate = `
+resource "rabbitmq_vhost" "test" {
+    name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user = "guest"
+    vhost = "${rabbitmq_vhost.test.name}"
+    permissions {
+        configure = ".*"
+        write = ".*"
+        read = ".*"
+    }
+}
+
+resource "rabbitmq_exchange" "test" {
+    name = "test"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        type = "topic"
+        alternate_exchange = "missing"
+    }
+}`
+
+const testAccExchangeConfig_argumentsJson = `
+resource "rabbitmq_vhost" "test" {
+    name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user = "guest"
+    vhost = "${rabbitmq_vhost.test.name}"
+    permissions {
+        configure = ".*"
+        write = ".*"
+        read = ".*"
+    }
+}
+
+resource "rabbitmq_exchange" "test" {
+    name = "test"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    settings {
+        type = "topic"
+        durable = true
+        arguments_json = jsonencode({
+            "hash-header" = "customer-id"
+            "x-weight" = 10
+            "x-flag" = true
+        })
+    }
+}`
+
+func testAccExchangeConfig_type(exchangeType string) string {
+	return fmt.Sprintf(`
+resource "rabbitmq_exchange" "test" {
+    name = "test"
+    settings {
+        type = "%s"
+    }
+}`, exchangeType)
+}
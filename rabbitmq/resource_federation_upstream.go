@@ -1,8 +1,12 @@
 package rabbitmq
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -19,6 +23,12 @@ func resourceFederationUpstream() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: customizeFederationUpstreamDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -42,6 +52,13 @@ func resourceFederationUpstream() *schema.Resource {
 				Description: "Set to `federation-upstream` by the underlying RabbitMQ provider. You do not set this attribute but will see it in state and plan output.",
 			},
 
+			"wait_for_links_running": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to wait, after creating or updating the upstream, until it has federation links and they are all running. Fails at the timeout with the errors of the links that are still not running, or when no policy uses the upstream.",
+			},
+
 			"definition": {
 				Type:        schema.TypeList,
 				Required:    true,
@@ -51,10 +68,24 @@ func resourceFederationUpstream() *schema.Resource {
 					Schema: map[string]*schema.Schema{
 						// applicable to both federated exchanges and queues
 						"uri": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Sensitive:   true,
-							Description: "The AMQP URI(s) for the upstream. Note that the URI may contain sensitive information, such as a password.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Sensitive:    true,
+							ExactlyOneOf: []string{"definition.0.uri", "definition.0.uris"},
+							Description:  "The AMQP URI for the upstream. Note that the URI may contain sensitive information, such as a password. Only one of `uri` and `uris` can be set.",
+						},
+
+						"uris": {
+							Type:         schema.TypeList,
+							Optional:     true,
+							Sensitive:    true,
+							MinItems:     1,
+							ExactlyOneOf: []string{"definition.0.uri", "definition.0.uris"},
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringIsNotEmpty,
+							},
+							Description: "The AMQP URIs for the upstream. Federation links connect to one of them, and to another one when the connection is lost, so that federation survives the failure of upstream nodes. Note that the URIs may contain sensitive information, such as passwords. Only one of `uri` and `uris` can be set.",
 						},
 
 						"prefetch_count": {
@@ -89,6 +120,27 @@ func resourceFederationUpstream() *schema.Resource {
 							Default:     false,
 							Description: "Determines how federation should interact with the validated user-id feature.",
 						},
+
+						"bind_nowait": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether to declare bindings of the link without waiting for the confirmation of the upstream, which speeds up the start of links with many bindings.",
+						},
+
+						"channel_use_mode": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"multiple", "single"}, false),
+							Description:  "Whether links use separate channels for publishing and consuming (`multiple`), or a single channel (`single`). Requires RabbitMQ 3.10 or later.",
+						},
+
+						"resource_cleanup_mode": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"default", "never"}, false),
+							Description:  "Whether the internal upstream queues and exchanges are deleted when the link stops (`default`), or kept (`never`). Requires RabbitMQ 3.13 or later.",
+						},
 						// applicable to federated exchanges only
 						"exchange": {
 							Type:        schema.TypeString,
@@ -114,12 +166,25 @@ func resourceFederationUpstream() *schema.Resource {
 							Optional:    true,
 							Description: "The expiry time (in milliseconds) for messages in the upstream queue for a federated exchange (see expires). This is only applicable to federated exchanges.",
 						},
+
+						"queue_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"classic", "quorum"}, false),
+							Description:  "The type of the upstream queue for a federated exchange, `classic` or `quorum`. This is only applicable to federated exchanges. Requires RabbitMQ 3.13 or later.",
+						},
 						// applicable to federated queues only
 						"queue": {
 							Type:        schema.TypeString,
 							Optional:    true,
 							Description: "The name of the upstream queue. This is only applicable to federated queues.",
 						},
+
+						"consumer_tag": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The consumer tag of the link on the upstream queue. This is only applicable to federated queues. Requires RabbitMQ 3.10 or later.",
+						},
 					},
 				},
 			},
@@ -128,7 +193,7 @@ func resourceFederationUpstream() *schema.Resource {
 }
 
 func CreateFederationUpstream(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name := d.Get("name").(string)
 	vhost := d.Get("vhost").(string)
@@ -146,18 +211,24 @@ func CreateFederationUpstream(d *schema.ResourceData, meta interface{}) error {
 	id := fmt.Sprintf("%s@%s", name, vhost)
 	d.SetId(id)
 
+	if d.Get("wait_for_links_running").(bool) {
+		if err := waitForFederationLinks(rmqc, vhost, name, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
+
 	return ReadFederationUpstream(d, meta)
 }
 
 func ReadFederationUpstream(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
 		return err
 	}
 
-	upstream, err := rmqc.GetFederationUpstream(vhost, name)
+	upstream, err := getFederationUpstream(rmqc, vhost, name)
 	if err != nil {
 		return checkDeleted(d, err)
 	}
@@ -168,12 +239,21 @@ func ReadFederationUpstream(d *schema.ResourceData, meta interface{}) error {
 	d.Set("vhost", upstream.Vhost)
 	d.Set("component", upstream.Component)
 
+	// wait_for_links_running only lives in Terraform, keep its default on import.
+	d.Set("wait_for_links_running", d.Get("wait_for_links_running").(bool))
+
+	// The URIs are read back into the attribute they were set with.
+	// With `uri`, additional URIs set outside of Terraform show up in `uris`.
 	var uri string
-	if len(upstream.Definition.Uri) > 0 {
+	var uris []string
+	if v, ok := d.Get("definition.0.uris").([]interface{}); ok && len(v) > 0 || len(upstream.Definition.Uri) > 1 {
+		uris = upstream.Definition.Uri
+	} else if len(upstream.Definition.Uri) > 0 {
 		uri = upstream.Definition.Uri[0]
 	}
 	defMap := map[string]interface{}{
 		"uri":             uri,
+		"uris":            uris,
 		"prefetch_count":  upstream.Definition.PrefetchCount,
 		"reconnect_delay": upstream.Definition.ReconnectDelay,
 		"ack_mode":        upstream.Definition.AckMode,
@@ -183,6 +263,12 @@ func ReadFederationUpstream(d *schema.ResourceData, meta interface{}) error {
 		"expires":         upstream.Definition.Expires,
 		"message_ttl":     upstream.Definition.MessageTTL,
 		"queue":           upstream.Definition.Queue,
+
+		"queue_type":            upstream.Definition.QueueType,
+		"bind_nowait":           upstream.Definition.BindNowait,
+		"consumer_tag":          upstream.Definition.ConsumerTag,
+		"channel_use_mode":      upstream.Definition.ChannelUseMode,
+		"resource_cleanup_mode": upstream.Definition.ResourceCleanupMode,
 	}
 
 	defList := [1]map[string]interface{}{defMap}
@@ -192,7 +278,7 @@ func ReadFederationUpstream(d *schema.ResourceData, meta interface{}) error {
 }
 
 func UpdateFederationUpstream(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -213,11 +299,17 @@ func UpdateFederationUpstream(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	if d.Get("wait_for_links_running").(bool) {
+		if err := waitForFederationLinks(rmqc, vhost, name, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
 	return ReadFederationUpstream(d, meta)
 }
 
 func DeleteFederationUpstream(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -244,12 +336,83 @@ func DeleteFederationUpstream(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+// federationUpstreamDefinition adds to rabbithole.FederationDefinition
+// the keys rabbit-hole does not support.
+type federationUpstreamDefinition struct {
+	rabbithole.FederationDefinition
+	QueueType           string `json:"queue-type,omitempty"`
+	BindNowait          bool   `json:"bind-nowait,omitempty"`
+	ConsumerTag         string `json:"consumer-tag,omitempty"`
+	ChannelUseMode      string `json:"channel-use-mode,omitempty"`
+	ResourceCleanupMode string `json:"resource-cleanup-mode,omitempty"`
+}
+
+type federationUpstream struct {
+	Name       string                       `json:"name"`
+	Vhost      string                       `json:"vhost"`
+	Component  string                       `json:"component"`
+	Definition federationUpstreamDefinition `json:"value"`
+}
+
+func getFederationUpstream(rmqc *rabbithole.Client, vhost string, name string) (*federationUpstream, error) {
+	path := fmt.Sprintf("parameters/%s/%s/%s", rabbithole.FederationUpstreamComponent, url.PathEscape(vhost), url.PathEscape(name))
+
+	upstream := &federationUpstream{}
+	if _, err := managementRequest(rmqc, http.MethodGet, path, nil, upstream); err != nil {
+		return nil, err
+	}
+
+	return upstream, nil
+}
+
+// federationUpstreamOptions are the options of federation upstreams
+// that need a recent version of RabbitMQ.
+var federationUpstreamOptions = []struct {
+	attribute string
+	major     int
+	minor     int
+}{
+	{"consumer_tag", 3, 10},
+	{"channel_use_mode", 3, 10},
+	{"queue_type", 3, 13},
+	{"resource_cleanup_mode", 3, 13},
+}
+
+// customizeFederationUpstreamDiff rejects the options that the server
+// does not support.
+func customizeFederationUpstreamDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	var version string
+	for _, option := range federationUpstreamOptions {
+		attribute := "definition.0." + option.attribute
+		if !diff.HasChange(attribute) || diff.Get(attribute).(string) == "" {
+			continue
+		}
+
+		if version == "" {
+			var err error
+			if version, err = serverVersion(meta.(*providerMeta).Client); err != nil {
+				return err
+			}
+		}
+
+		if !versionAtLeast(version, option.major, option.minor) {
+			return fmt.Errorf("%s requires RabbitMQ %d.%d or later, connected to %s", option.attribute, option.major, option.minor, version)
+		}
+	}
+
+	return nil
+}
+
 func putFederationUpstream(rmqc *rabbithole.Client, vhost string, name string, defMap map[string]interface{}) error {
-	definition := rabbithole.FederationDefinition{}
+	definition := federationUpstreamDefinition{}
 
 	log.Printf("[DEBUG] RabbitMQ: Attempting to put federation definition for %s@%s: %#v", name, vhost, defMap)
 
-	if v, ok := defMap["uri"].(string); ok {
+	if v, ok := defMap["uris"].([]interface{}); ok && len(v) > 0 {
+		for _, uri := range v {
+			definition.Uri = append(definition.Uri, uri.(string))
+		}
+	} else if v, ok := defMap["uri"].(string); ok {
 		definition.Uri = []string{v}
 	}
 
@@ -289,9 +452,29 @@ func putFederationUpstream(rmqc *rabbithole.Client, vhost string, name string, d
 		definition.Queue = v
 	}
 
+	if v, ok := defMap["queue_type"].(string); ok {
+		definition.QueueType = v
+	}
+
+	if v, ok := defMap["bind_nowait"].(bool); ok {
+		definition.BindNowait = v
+	}
+
+	if v, ok := defMap["consumer_tag"].(string); ok {
+		definition.ConsumerTag = v
+	}
+
+	if v, ok := defMap["channel_use_mode"].(string); ok {
+		definition.ChannelUseMode = v
+	}
+
+	if v, ok := defMap["resource_cleanup_mode"].(string); ok {
+		definition.ResourceCleanupMode = v
+	}
+
 	log.Printf("[DEBUG] RabbitMQ: Attempting to declare federation upstream for %s@%s: %#v", name, vhost, definition)
 
-	resp, err := rmqc.PutFederationUpstream(vhost, name, definition)
+	resp, err := rmqc.PutRuntimeParameter(rabbithole.FederationUpstreamComponent, vhost, name, definition)
 	log.Printf("[DEBUG] RabbitMQ: Federation upstream declare response: %#v", resp)
 	if err != nil {
 		return err
@@ -0,0 +1,350 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+)
+
+const federationUpstreamSetComponent = "federation-upstream-set"
+
+// federationUpstreamSetOverrides maps the overrides of the upstreams of
+// a set onto the keys of the upstream definition they override.
+var federationUpstreamSetOverrides = []struct {
+	attribute string
+	key       string
+	valueType schema.ValueType
+}{
+	{"exchange", "exchange", schema.TypeString},
+	{"queue", "queue", schema.TypeString},
+	{"prefetch_count", "prefetch-count", schema.TypeInt},
+	{"reconnect_delay", "reconnect-delay", schema.TypeInt},
+	{"ack_mode", "ack-mode", schema.TypeString},
+	{"max_hops", "max-hops", schema.TypeInt},
+	{"expires", "expires", schema.TypeInt},
+	{"message_ttl", "message-ttl", schema.TypeInt},
+}
+
+func resourceFederationUpstreamSet() *schema.Resource {
+	return &schema.Resource{
+		Create:      CreateFederationUpstreamSet,
+		Read:        ReadFederationUpstreamSet,
+		Update:      UpdateFederationUpstreamSet,
+		Delete:      DeleteFederationUpstreamSet,
+		Description: "The `rabbitmq_federation_upstream_set` resource creates and manages a federation upstream set, which policies can federate from with the `federation-upstream-set` key.",
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: customizeFederationUpstreamSetDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the federation upstream set.",
+			},
+
+			"vhost": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The vhost to create the resource in.",
+			},
+
+			"upstream": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "An upstream of the set, with the settings overriding the ones of the upstream.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the federation upstream.",
+						},
+
+						"exchange": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Overrides the name of the upstream exchange.",
+						},
+
+						"queue": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Overrides the name of the upstream queue.",
+						},
+
+						"prefetch_count": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Overrides the maximum number of unacknowledged messages in flight over a link. `0` keeps the setting of the upstream.",
+						},
+
+						"reconnect_delay": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Overrides the time in seconds to wait before reconnecting a link. `0` keeps the setting of the upstream.",
+						},
+
+						"ack_mode": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"on-confirm", "on-publish", "no-ack"}, false),
+							Description:  "Overrides how the link acknowledges messages. Valid values are `on-confirm`, `on-publish`, and `no-ack`.",
+						},
+
+						"max_hops": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Overrides the maximum number of federation links that messages can traverse. `0` keeps the setting of the upstream.",
+						},
+
+						"expires": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Overrides the expiry time (in milliseconds) of the upstream queue for a federated exchange. `0` keeps the setting of the upstream.",
+						},
+
+						"message_ttl": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Overrides the expiry time (in milliseconds) of messages in the upstream queue for a federated exchange. `0` keeps the setting of the upstream.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func CreateFederationUpstreamSet(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	name := d.Get("name").(string)
+	vhost := d.Get("vhost").(string)
+
+	if err := putFederationUpstreamSet(rmqc, vhost, name, d.Get("upstream").([]interface{})); err != nil {
+		return err
+	}
+
+	id := fmt.Sprintf("%s@%s", name, vhost)
+	d.SetId(id)
+
+	return ReadFederationUpstreamSet(d, meta)
+}
+
+func ReadFederationUpstreamSet(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	name, vhost, err := parseResourceId(d)
+	if err != nil {
+		return err
+	}
+
+	parameter, err := rmqc.GetRuntimeParameter(federationUpstreamSetComponent, vhost, name)
+	if err != nil {
+		return checkDeleted(d, err)
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: Federation upstream set retrieved for %s: %#v", d.Id(), parameter)
+
+	values, ok := parameter.Value.([]interface{})
+	if !ok {
+		return fmt.Errorf("Unable to parse federation upstream set %s: %#v", d.Id(), parameter.Value)
+	}
+
+	upstreams := make([]map[string]interface{}, 0, len(values))
+	for _, v := range values {
+		value, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("Unable to parse federation upstream set %s: %#v", d.Id(), parameter.Value)
+		}
+
+		upstream := map[string]interface{}{
+			"name": value["upstream"],
+		}
+		for _, override := range federationUpstreamSetOverrides {
+			switch override.valueType {
+			case schema.TypeInt:
+				if n, ok := value[override.key].(float64); ok {
+					upstream[override.attribute] = int(n)
+				}
+			default:
+				upstream[override.attribute] = value[override.key]
+			}
+		}
+		upstreams = append(upstreams, upstream)
+	}
+
+	d.Set("name", parameter.Name)
+	d.Set("vhost", parameter.Vhost)
+	d.Set("upstream", upstreams)
+
+	return nil
+}
+
+func UpdateFederationUpstreamSet(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	name, vhost, err := parseResourceId(d)
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange("upstream") {
+		if err := putFederationUpstreamSet(rmqc, vhost, name, d.Get("upstream").([]interface{})); err != nil {
+			return err
+		}
+	}
+
+	return ReadFederationUpstreamSet(d, meta)
+}
+
+func DeleteFederationUpstreamSet(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	name, vhost, err := parseResourceId(d)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: Attempting to delete federation upstream set for %s", d.Id())
+
+	resp, err := rmqc.DeleteRuntimeParameter(federationUpstreamSetComponent, vhost, name)
+	log.Printf("[DEBUG] RabbitMQ: Federation upstream set delete response: %#v", resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == 404 {
+		// the upstream set was automatically deleted
+		return nil
+	}
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("Error deleting RabbitMQ federation upstream set: %s", resp.Status)
+	}
+
+	return nil
+}
+
+func putFederationUpstreamSet(rmqc *rabbithole.Client, vhost string, name string, upstreams []interface{}) error {
+	names := make([]string, 0, len(upstreams))
+	value := make([]map[string]interface{}, 0, len(upstreams))
+	for _, u := range upstreams {
+		upstream := u.(map[string]interface{})
+		names = append(names, upstream["name"].(string))
+
+		v := map[string]interface{}{
+			"upstream": upstream["name"],
+		}
+		for _, override := range federationUpstreamSetOverrides {
+			switch x := upstream[override.attribute].(type) {
+			case string:
+				if x != "" {
+					v[override.key] = x
+				}
+			case int:
+				if x != 0 {
+					v[override.key] = x
+				}
+			}
+		}
+		value = append(value, v)
+	}
+
+	// RabbitMQ accepts sets referring to upstreams that do not exist,
+	// whose links then silently never start.
+	missing, err := missingFederationUpstreams(rmqc, vhost, names)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("Federation upstream set %s@%s refers to upstreams that do not exist: %s", name, vhost, strings.Join(missing, ", "))
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: Attempting to declare federation upstream set for %s@%s: %#v", name, vhost, value)
+
+	resp, err := rmqc.PutRuntimeParameter(federationUpstreamSetComponent, vhost, name, value)
+	log.Printf("[DEBUG] RabbitMQ: Federation upstream set declare response: %#v", resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("Error creating RabbitMQ federation upstream set: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// missingFederationUpstreams returns the names of the upstreams that do not
+// exist in the vhost.
+func missingFederationUpstreams(rmqc *rabbithole.Client, vhost string, names []string) ([]string, error) {
+	upstreams, err := rmqc.ListFederationUpstreamsIn(vhost)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(upstreams))
+	for _, upstream := range upstreams {
+		existing[upstream.Name] = true
+	}
+
+	var missing []string
+	for _, name := range names {
+		if !existing[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+
+	return missing, nil
+}
+
+// customizeFederationUpstreamSetDiff checks that the upstreams of the set
+// exist. As they may be created by the same apply, missing upstreams are
+// only a warning at plan time, and an error when applying.
+func customizeFederationUpstreamSetDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.HasChange("upstream") || !diff.NewValueKnown("vhost") {
+		return nil
+	}
+
+	var names []string
+	for i := range diff.Get("upstream").([]interface{}) {
+		attribute := fmt.Sprintf("upstream.%d.name", i)
+		if diff.NewValueKnown(attribute) {
+			names = append(names, diff.Get(attribute).(string))
+		}
+	}
+
+	vhost := diff.Get("vhost").(string)
+	missing, err := missingFederationUpstreams(meta.(*providerMeta).Client, vhost, names)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		addPlanWarning(ctx, diag.Diagnostic{
+			Summary: "Federation upstreams do not exist",
+			Detail:  fmt.Sprintf("The federation upstream set %s refers to upstreams that do not exist in vhost %s: %s. Applying fails unless they are created first.", diff.Get("name"), vhost, strings.Join(missing, ", ")),
+		})
+	}
+
+	return nil
+}
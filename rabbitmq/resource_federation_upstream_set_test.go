@@ -0,0 +1,172 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccFederationUpstreamSet(t *testing.T) {
+	resourceName := "rabbitmq_federation_upstream_set.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccFederationUpstreamSetCheckDestroy("test", "test"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFederationUpstreamSetConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccFederationUpstreamSetCheck(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "upstream.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "upstream.0.name", "dc1"),
+					resource.TestCheckResourceAttr(resourceName, "upstream.0.exchange", ""),
+					resource.TestCheckResourceAttr(resourceName, "upstream.1.name", "dc2"),
+					resource.TestCheckResourceAttr(resourceName, "upstream.1.exchange", "events-dc2"),
+					resource.TestCheckResourceAttr(resourceName, "upstream.1.prefetch_count", "100"),
+					resource.TestCheckResourceAttr(resourceName, "upstream.1.ack_mode", "on-publish"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccFederationUpstreamSetConfig_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccFederationUpstreamSetCheck(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "upstream.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "upstream.0.name", "dc2"),
+					resource.TestCheckResourceAttr(resourceName, "upstream.0.exchange", ""),
+					resource.TestCheckResourceAttr(resourceName, "upstream.0.prefetch_count", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccFederationUpstreamSet_missingUpstream(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccFederationUpstreamSetCheckDestroy("test", "test"),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccFederationUpstreamSetConfig_missingUpstream,
+				ExpectError: regexp.MustCompile("Federation upstream set test@test refers to upstreams that do not exist: missing"),
+			},
+		},
+	})
+}
+
+func testAccFederationUpstreamSetCheck(rn string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		name, vhost, err := parseId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+		if _, err := rmqc.GetRuntimeParameter(federationUpstreamSetComponent, vhost, name); err != nil {
+			return fmt.Errorf("Error retrieving federation upstream set: %s", err)
+		}
+
+		return nil
+	}
+}
+
+func testAccFederationUpstreamSetCheckDestroy(name string, vhost string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+
+		if _, err := rmqc.GetRuntimeParameter(federationUpstreamSetComponent, vhost, name); err == nil {
+			return fmt.Errorf("Federation upstream set %s@%s still exists", name, vhost)
+		} else if !isNotFound(err) {
+			return err
+		}
+
+		return nil
+	}
+}
+
+const testAccFederationUpstreamSetConfig_base = `
+resource "rabbitmq_vhost" "test" {
+    name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user = "guest"
+    vhost = rabbitmq_vhost.test.name
+    permissions {
+        configure = ".*"
+        write = ".*"
+        read = ".*"
+    }
+}
+
+resource "rabbitmq_federation_upstream" "dc1" {
+    name = "dc1"
+    vhost = rabbitmq_permissions.guest.vhost
+    definition {
+        uri = "amqp://dc1"
+    }
+}
+
+resource "rabbitmq_federation_upstream" "dc2" {
+    name = "dc2"
+    vhost = rabbitmq_permissions.guest.vhost
+    definition {
+        uri = "amqp://dc2"
+    }
+}
+`
+
+const testAccFederationUpstreamSetConfig_basic = testAccFederationUpstreamSetConfig_base + `
+resource "rabbitmq_federation_upstream_set" "test" {
+    name = "test"
+    vhost = rabbitmq_permissions.guest.vhost
+
+    upstream {
+        name = rabbitmq_federation_upstream.dc1.name
+    }
+
+    upstream {
+        name = rabbitmq_federation_upstream.dc2.name
+        exchange = "events-dc2"
+        prefetch_count = 100
+        ack_mode = "on-publish"
+    }
+}`
+
+const testAccFederationUpstreamSetConfig_update = testAccFederationUpstreamSetConfig_base + `
+resource "rabbitmq_federation_upstream_set" "test" {
+    name = "test"
+    vhost = rabbitmq_permissions.guest.vhost
+
+    upstream {
+        name = rabbitmq_federation_upstream.dc2.name
+    }
+}`
+
+const testAccFederationUpstreamSetConfig_missingUpstream = testAccFederationUpstreamSetConfig_base + `
+resource "rabbitmq_federation_upstream_set" "test" {
+    name = "test"
+    vhost = rabbitmq_permissions.guest.vhost
+
+    upstream {
+        name = rabbitmq_federation_upstream.dc1.name
+    }
+
+    upstream {
+        name = "missing"
+    }
+}`
@@ -96,6 +96,125 @@ func TestAccFederationUpstream_defaults(t *testing.T) {
 	})
 }
 
+func TestAccFederationUpstream_uris(t *testing.T) {
+	var upstream rabbithole.FederationUpstream
+	resourceName := "rabbitmq_federation_upstream.foo"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccFederationUpstreamCheckDestroy(&upstream),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFederationUpstream_uris(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccFederationUpstreamCheck(resourceName, &upstream),
+					testAccFederationUpstreamCheckUris(resourceName, []string{"amqp://server-1", "amqp://server-2", "amqp://server-3"}),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.uri", ""),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.uris.#", "3"),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.uris.0", "amqp://server-1"),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.uris.2", "amqp://server-3"),
+				),
+			},
+			{
+				Config: testAccFederationUpstream_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccFederationUpstreamCheck(resourceName, &upstream),
+					testAccFederationUpstreamCheckUris(resourceName, []string{"amqp://server-name"}),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.uri", "amqp://server-name"),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.uris.#", "0"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccFederationUpstream_options(t *testing.T) {
+	var upstream rabbithole.FederationUpstream
+	resourceName := "rabbitmq_federation_upstream.foo"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccFederationUpstreamCheckDestroy(&upstream),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFederationUpstream_options(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccFederationUpstreamCheck(resourceName, &upstream),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.queue_type", "quorum"),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.bind_nowait", "true"),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.consumer_tag", "federation-link-foo"),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.channel_use_mode", "single"),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.resource_cleanup_mode", "never"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccFederationUpstream_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccFederationUpstreamCheck(resourceName, &upstream),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.queue_type", ""),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.bind_nowait", "false"),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.consumer_tag", ""),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.channel_use_mode", ""),
+					resource.TestCheckResourceAttr(resourceName, "definition.0.resource_cleanup_mode", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccFederationUpstream_waitForLinksRunning(t *testing.T) {
+	var upstream rabbithole.FederationUpstream
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccFederationUpstreamCheckDestroy(&upstream),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccFederationUpstream_waitForLinksRunning(),
+				ExpectError: regexp.MustCompile("Timeout waiting for the links of federation upstream foo@test to be running: exchange foo \\((starting|error)"),
+			},
+		},
+	})
+}
+
+// The policy using the upstream is created at the same time as the upstream,
+// so the wait starts before the upstream has links.
+func TestAccFederationUpstream_waitForLinksAppearing(t *testing.T) {
+	var upstream rabbithole.FederationUpstream
+	resourceName := "rabbitmq_federation_upstream.local"
+	dataSourceName := "data.rabbitmq_federation_links.local"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccFederationUpstreamCheckDestroy(&upstream),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFederationUpstream_waitForLinksAppearing(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccFederationUpstreamCheck(resourceName, &upstream),
+					resource.TestCheckResourceAttr(dataSourceName, "links.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "links.0.local_object", "federated"),
+					resource.TestCheckResourceAttr(dataSourceName, "all_running", "true"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccFederationUpstream_validation(t *testing.T) {
 	var upstream rabbithole.FederationUpstream
 
@@ -112,6 +231,31 @@ func TestAccFederationUpstream_validation(t *testing.T) {
 	})
 }
 
+func testAccFederationUpstreamCheckUris(rn string, uris []string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		name, vhost, err := parseId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+		upstream, err := rmqc.GetFederationUpstream(vhost, name)
+		if err != nil {
+			return fmt.Errorf("Error retrieving federation upstream: %s", err)
+		}
+
+		if !stringSlicesEqual(upstream.Definition.Uri, uris) {
+			return fmt.Errorf("Expected the URIs %v, got %v", uris, upstream.Definition.Uri)
+		}
+		return nil
+	}
+}
+
 func testAccFederationUpstreamCheck(rn string, upstream *rabbithole.FederationUpstream) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -127,7 +271,7 @@ func testAccFederationUpstreamCheck(rn string, upstream *rabbithole.FederationUp
 		name := id[0]
 		vhost := id[1]
 
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		upstreams, err := rmqc.ListFederationUpstreamsIn(vhost)
 		if err != nil {
 			return fmt.Errorf("Error retrieving federation upstreams: %s", err)
@@ -146,7 +290,7 @@ func testAccFederationUpstreamCheck(rn string, upstream *rabbithole.FederationUp
 
 func testAccFederationUpstreamCheckDestroy(upstream *rabbithole.FederationUpstream) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 
 		upstreams, err := rmqc.ListFederationUpstreamsIn(upstream.Vhost)
 		if err != nil {
@@ -242,6 +386,130 @@ resource "rabbitmq_federation_upstream" "foo" {
 `
 }
 
+func testAccFederationUpstream_uris() string {
+	return testAccFederationUpstream_baseConfig() + `
+resource "rabbitmq_federation_upstream" "foo" {
+		name = "foo"
+		vhost = rabbitmq_permissions.guest.vhost
+
+		definition {
+				uris = ["amqp://server-1", "amqp://server-2", "amqp://server-3"]
+		}
+}
+`
+}
+
+func testAccFederationUpstream_options() string {
+	return testAccFederationUpstream_baseConfig() + `
+resource "rabbitmq_federation_upstream" "foo" {
+		name = "foo"
+		vhost = rabbitmq_permissions.guest.vhost
+
+		definition {
+				uri = "amqp://server-name"
+				queue_type = "quorum"
+				bind_nowait = true
+				consumer_tag = "federation-link-foo"
+				channel_use_mode = "single"
+				resource_cleanup_mode = "never"
+		}
+}
+`
+}
+
+func testAccFederationUpstream_waitForLinksRunning() string {
+	return testAccFederationUpstream_baseConfig() + `
+resource "rabbitmq_exchange" "foo" {
+		name = "foo"
+		vhost = rabbitmq_permissions.guest.vhost
+
+		settings {
+				type = "topic"
+		}
+}
+
+resource "rabbitmq_policy" "foo" {
+		name = "foo"
+		vhost = rabbitmq_permissions.guest.vhost
+
+		policy {
+				pattern = "^${rabbitmq_exchange.foo.name}$"
+				priority = 1
+				apply_to = "exchanges"
+
+				definition = {
+						federation-upstream = "foo"
+				}
+		}
+}
+
+resource "rabbitmq_federation_upstream" "foo" {
+		name = "foo"
+		vhost = rabbitmq_policy.foo.vhost
+
+		wait_for_links_running = true
+
+		definition {
+				uri = "amqp://localhost:1"
+				reconnect_delay = 1
+		}
+
+		timeouts {
+				create = "10s"
+		}
+}
+`
+}
+
+func testAccFederationUpstream_waitForLinksAppearing() string {
+	return testAccFederationUpstream_baseConfig() + `
+resource "rabbitmq_exchange" "federated" {
+		name = "federated"
+		vhost = rabbitmq_permissions.guest.vhost
+
+		settings {
+				type = "topic"
+		}
+}
+
+resource "rabbitmq_policy" "federated" {
+		name = "federated"
+		vhost = rabbitmq_permissions.guest.vhost
+
+		policy {
+				pattern = "^${rabbitmq_exchange.federated.name}$"
+				priority = 1
+				apply_to = "exchanges"
+
+				definition = {
+						federation-upstream = "local"
+				}
+		}
+}
+
+resource "rabbitmq_federation_upstream" "local" {
+		name = "local"
+		vhost = rabbitmq_permissions.guest.vhost
+
+		wait_for_links_running = true
+
+		definition {
+				uri = "amqp://"
+				exchange = "amq.topic"
+		}
+
+		timeouts {
+				create = "1m"
+		}
+}
+
+data "rabbitmq_federation_links" "local" {
+		vhost = rabbitmq_federation_upstream.local.vhost
+		upstream = rabbitmq_federation_upstream.local.name
+}
+`
+}
+
 func testAccFederationUpstream_validation() string {
 	return testAccFederationUpstream_baseConfig() + `
 resource "rabbitmq_federation_upstream" "foo" {
@@ -1,6 +1,7 @@
 package rabbitmq
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"strconv"
@@ -9,6 +10,8 @@ import (
 	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceOperatorPolicy() *schema.Resource {
@@ -21,6 +24,7 @@ func resourceOperatorPolicy() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: customizePolicyDiff(true),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -45,9 +49,10 @@ func resourceOperatorPolicy() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"pattern": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "A pattern to match an exchange or queue name.",
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validatePolicyPattern,
+							Description:  "A pattern to match an exchange or queue name. It must be a valid regular expression.",
 						},
 
 						"priority": {
@@ -57,15 +62,27 @@ func resourceOperatorPolicy() *schema.Resource {
 						},
 
 						"apply_to": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "The type of object to apply the policy to.",
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(policyApplyToValues, false),
+							Description:  "The objects to apply the operator policy to. Can be `all`, `queues` or `exchanges`, or `classic_queues`, `quorum_queues` or `streams` on RabbitMQ 3.12 and later.",
 						},
 
 						"definition": {
-							Type:        schema.TypeMap,
-							Required:    true,
-							Description: "Key/value pairs of the operator policy definition. See the RabbitMQ documentation for definition references and examples.",
+							Type:             schema.TypeMap,
+							Optional:         true,
+							ExactlyOneOf:     []string{"policy.0.definition", "policy.0.definition_json"},
+							ValidateDiagFunc: validatePolicyDefinition(true),
+							Description:      "Key/value pairs of the operator policy definition. See the RabbitMQ documentation for definition references and examples. Values are sent as strings, except integers. Use `definition_json` for other types.",
+						},
+
+						"definition_json": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ExactlyOneOf:     []string{"policy.0.definition", "policy.0.definition_json"},
+							ValidateDiagFunc: validatePolicyDefinitionJson(true),
+							DiffSuppressFunc: structure.SuppressJsonDiff,
+							Description:      "The operator policy definition as a JSON object. Values keep their JSON types, e.g. lists, booleans or numeric strings.",
 						},
 					},
 				},
@@ -75,7 +92,7 @@ func resourceOperatorPolicy() *schema.Resource {
 }
 
 func CreateOperatorPolicy(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name := d.Get("name").(string)
 	vhost := d.Get("vhost").(string)
@@ -96,7 +113,7 @@ func CreateOperatorPolicy(d *schema.ResourceData, meta interface{}) error {
 }
 
 func ReadOperatorPolicy(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -119,23 +136,32 @@ func ReadOperatorPolicy(d *schema.ResourceData, meta interface{}) error {
 	p["priority"] = operatorPolicy.Priority
 	p["apply_to"] = operatorPolicy.ApplyTo
 
-	operatorPolicyDefinition := make(map[string]interface{})
-	for key, value := range operatorPolicy.Definition {
-		switch v := value.(type) {
-		case float64:
-			value = strconv.FormatFloat(v, 'f', -1, 64)
-		case []interface{}:
-			var nodes []string
-			for _, node := range v {
-				if n, ok := node.(string); ok {
-					nodes = append(nodes, n)
+	// The definition is read back into the attribute it was set with.
+	if v, ok := d.Get("policy.0.definition_json").(string); ok && v != "" {
+		bytes, err := json.Marshal(operatorPolicy.Definition)
+		if err != nil {
+			return err
+		}
+		p["definition_json"] = string(bytes)
+	} else {
+		operatorPolicyDefinition := make(map[string]interface{})
+		for key, value := range operatorPolicy.Definition {
+			switch v := value.(type) {
+			case float64:
+				value = strconv.FormatFloat(v, 'f', -1, 64)
+			case []interface{}:
+				var nodes []string
+				for _, node := range v {
+					if n, ok := node.(string); ok {
+						nodes = append(nodes, n)
+					}
 				}
+				value = strings.Join(nodes, ",")
 			}
-			value = strings.Join(nodes, ",")
+			operatorPolicyDefinition[key] = value
 		}
-		operatorPolicyDefinition[key] = value
+		p["definition"] = operatorPolicyDefinition
 	}
-	p["definition"] = operatorPolicyDefinition
 	setOperatorPolicy[0] = p
 
 	d.Set("policy", setOperatorPolicy)
@@ -144,7 +170,7 @@ func ReadOperatorPolicy(d *schema.ResourceData, meta interface{}) error {
 }
 
 func UpdateOperatorPolicy(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -169,7 +195,7 @@ func UpdateOperatorPolicy(d *schema.ResourceData, meta interface{}) error {
 }
 
 func DeleteOperatorPolicy(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -226,6 +252,14 @@ func putOperatorPolicy(rmqc *rabbithole.Client, vhost string, name string, opera
 		operatorPolicy.Definition = v
 	}
 
+	if v, ok := operatorPolicyMap["definition_json"].(string); ok && v != "" {
+		definition, err := policyDefinitionFromJSON(v)
+		if err != nil {
+			return err
+		}
+		operatorPolicy.Definition = definition
+	}
+
 	log.Printf("[DEBUG] RabbitMQ: Attempting to declare operator policy for %s@%s: %#v", name, vhost, operatorPolicy)
 
 	resp, err := rmqc.PutOperatorPolicy(vhost, name, operatorPolicy)
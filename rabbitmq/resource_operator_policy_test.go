@@ -2,6 +2,7 @@ package rabbitmq
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -45,6 +46,39 @@ func TestAccOperatorPolicy(t *testing.T) {
 	})
 }
 
+func TestAccOperatorPolicy_definitionJson(t *testing.T) {
+	var operatorPolicy rabbithole.OperatorPolicy
+	resourceName := "rabbitmq_operator_policy.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccOperatorPolicyCheckDestroy(&operatorPolicy),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOperatorPolicyConfig_definitionJson,
+				Check: resource.ComposeTestCheckFunc(
+					testAccOperatorPolicyCheck(resourceName, &operatorPolicy),
+					resource.TestCheckNoResourceAttr(resourceName, "policy.0.definition.%"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccOperatorPolicy_invalidKey(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccOperatorPolicyConfig_invalidKey,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Policy key "ha-mode" is not allowed in operator policies`),
+			},
+		},
+	})
+}
+
 func testAccOperatorPolicyCheck(rn string, operatorPolicy *rabbithole.OperatorPolicy) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -56,7 +90,7 @@ func testAccOperatorPolicyCheck(rn string, operatorPolicy *rabbithole.OperatorPo
 			return fmt.Errorf("operator policy id not set")
 		}
 
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		operatorPolicyParts := strings.Split(rs.Primary.ID, "@")
 
 		operatorPolicies, err := rmqc.ListOperatorPolicies()
@@ -77,7 +111,7 @@ func testAccOperatorPolicyCheck(rn string, operatorPolicy *rabbithole.OperatorPo
 
 func testAccOperatorPolicyCheckDestroy(operatorPolicy *rabbithole.OperatorPolicy) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 
 		operatorPolicies, err := rmqc.ListOperatorPolicies()
 		if err != nil {
@@ -150,3 +184,46 @@ resource "rabbitmq_operator_policy" "test" {
         }
     }
 }`
+
+const testAccOperatorPolicyConfig_definitionJson = `
+resource "rabbitmq_vhost" "test" {
+    name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user = "guest"
+    vhost = "${rabbitmq_vhost.test.name}"
+    permissions {
+        configure = ".*"
+        write = ".*"
+        read = ".*"
+    }
+}
+
+resource "rabbitmq_operator_policy" "test" {
+    name = "test"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    policy {
+        pattern = ".*"
+        priority = 1
+        apply_to = "queues"
+        definition_json = jsonencode({
+            "max-length" = 10000
+            "expires" = 60000
+        })
+    }
+}`
+
+const testAccOperatorPolicyConfig_invalidKey = `
+resource "rabbitmq_operator_policy" "test" {
+    name = "test"
+    vhost = "/"
+    policy {
+        pattern = ".*"
+        priority = 1
+        apply_to = "queues"
+        definition = {
+            ha-mode = "all"
+        }
+    }
+}`
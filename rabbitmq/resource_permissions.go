@@ -68,7 +68,7 @@ func resourcePermissions() *schema.Resource {
 }
 
 func CreatePermissions(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	user := d.Get("user").(string)
 	vhost := d.Get("vhost").(string)
@@ -90,7 +90,7 @@ func CreatePermissions(d *schema.ResourceData, meta interface{}) error {
 }
 
 func ReadPermissions(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	user, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -119,7 +119,7 @@ func ReadPermissions(d *schema.ResourceData, meta interface{}) error {
 }
 
 func UpdatePermissions(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	user, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -144,7 +144,7 @@ func UpdatePermissions(d *schema.ResourceData, meta interface{}) error {
 }
 
 func DeletePermissions(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	user, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -82,7 +82,7 @@ func testAccPermissionsCheck(rn string, permissionInfo *rabbithole.PermissionInf
 			return fmt.Errorf("permission id not set")
 		}
 
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		perms, err := rmqc.ListPermissions()
 		if err != nil {
 			return fmt.Errorf("Error retrieving permissions: %s", err)
@@ -102,7 +102,7 @@ func testAccPermissionsCheck(rn string, permissionInfo *rabbithole.PermissionInf
 
 func testAccPermissionsCheckDestroy(permissionInfo *rabbithole.PermissionInfo) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		perms, err := rmqc.ListPermissions()
 		if err != nil {
 			return fmt.Errorf("Error retrieving permissions: %s", err)
@@ -1,6 +1,7 @@
 package rabbitmq
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"strconv"
@@ -9,6 +10,8 @@ import (
 	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourcePolicy() *schema.Resource {
@@ -21,6 +24,7 @@ func resourcePolicy() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: customizePolicyDiff(false),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -45,9 +49,10 @@ func resourcePolicy() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"pattern": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "A pattern to match an exchange or queue name.",
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validatePolicyPattern,
+							Description:  "A pattern to match an exchange or queue name. It must be a valid regular expression.",
 						},
 
 						"priority": {
@@ -57,15 +62,27 @@ func resourcePolicy() *schema.Resource {
 						},
 
 						"apply_to": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Can either be `exchanges`, `queues`, or `all`.",
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(policyApplyToValues, false),
+							Description:  "The objects to apply the policy to. Can be `all`, `queues` or `exchanges`, or `classic_queues`, `quorum_queues` or `streams` on RabbitMQ 3.12 and later.",
 						},
 
 						"definition": {
-							Type:        schema.TypeMap,
-							Required:    true,
-							Description: "Key/value pairs of the policy definition. See the RabbitMQ documentation for definition references and examples.",
+							Type:             schema.TypeMap,
+							Optional:         true,
+							ExactlyOneOf:     []string{"policy.0.definition", "policy.0.definition_json"},
+							ValidateDiagFunc: validatePolicyDefinition(false),
+							Description:      "Key/value pairs of the policy definition. See the RabbitMQ documentation for definition references and examples. Values are sent as strings, except integers. Use `definition_json` for other types.",
+						},
+
+						"definition_json": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ExactlyOneOf:     []string{"policy.0.definition", "policy.0.definition_json"},
+							ValidateDiagFunc: validatePolicyDefinitionJson(false),
+							DiffSuppressFunc: structure.SuppressJsonDiff,
+							Description:      "The policy definition as a JSON object. Values keep their JSON types, e.g. lists, booleans or numeric strings.",
 						},
 					},
 				},
@@ -75,7 +92,7 @@ func resourcePolicy() *schema.Resource {
 }
 
 func CreatePolicy(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name := d.Get("name").(string)
 	vhost := d.Get("vhost").(string)
@@ -97,7 +114,7 @@ func CreatePolicy(d *schema.ResourceData, meta interface{}) error {
 }
 
 func ReadPolicy(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -120,23 +137,32 @@ func ReadPolicy(d *schema.ResourceData, meta interface{}) error {
 	p["priority"] = policy.Priority
 	p["apply_to"] = policy.ApplyTo
 
-	policyDefinition := make(map[string]interface{})
-	for key, value := range policy.Definition {
-		switch v := value.(type) {
-		case float64:
-			value = strconv.FormatFloat(v, 'f', -1, 64)
-		case []interface{}:
-			var nodes []string
-			for _, node := range v {
-				if n, ok := node.(string); ok {
-					nodes = append(nodes, n)
+	// The definition is read back into the attribute it was set with.
+	if v, ok := d.Get("policy.0.definition_json").(string); ok && v != "" {
+		bytes, err := json.Marshal(policy.Definition)
+		if err != nil {
+			return err
+		}
+		p["definition_json"] = string(bytes)
+	} else {
+		policyDefinition := make(map[string]interface{})
+		for key, value := range policy.Definition {
+			switch v := value.(type) {
+			case float64:
+				value = strconv.FormatFloat(v, 'f', -1, 64)
+			case []interface{}:
+				var nodes []string
+				for _, node := range v {
+					if n, ok := node.(string); ok {
+						nodes = append(nodes, n)
+					}
 				}
+				value = strings.Join(nodes, ",")
 			}
-			value = strings.Join(nodes, ",")
+			policyDefinition[key] = value
 		}
-		policyDefinition[key] = value
+		p["definition"] = policyDefinition
 	}
-	p["definition"] = policyDefinition
 	setPolicy[0] = p
 
 	d.Set("policy", setPolicy)
@@ -145,7 +171,7 @@ func ReadPolicy(d *schema.ResourceData, meta interface{}) error {
 }
 
 func UpdatePolicy(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -170,7 +196,7 @@ func UpdatePolicy(d *schema.ResourceData, meta interface{}) error {
 }
 
 func DeletePolicy(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -236,6 +262,14 @@ func putPolicy(rmqc *rabbithole.Client, vhost string, name string, policyMap map
 		policy.Definition = v
 	}
 
+	if v, ok := policyMap["definition_json"].(string); ok && v != "" {
+		definition, err := policyDefinitionFromJSON(v)
+		if err != nil {
+			return err
+		}
+		policy.Definition = definition
+	}
+
 	log.Printf("[DEBUG] RabbitMQ: Attempting to declare policy for %s@%s: %#v", name, vhost, policy)
 
 	resp, err := rmqc.PutPolicy(vhost, name, policy)
@@ -250,3 +284,17 @@ func putPolicy(rmqc *rabbithole.Client, vhost string, name string, policyMap map
 
 	return nil
 }
+
+// policyDefinitionFromJSON decodes the `definition_json` of a policy or an
+// operator policy. Numbers are kept exactly as written.
+func policyDefinitionFromJSON(definitionJson string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(definitionJson))
+	decoder.UseNumber()
+
+	var definition map[string]interface{}
+	if err := decoder.Decode(&definition); err != nil {
+		return nil, fmt.Errorf("could not decode definition_json: %w", err)
+	}
+
+	return definition, nil
+}
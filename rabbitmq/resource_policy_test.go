@@ -2,6 +2,7 @@ package rabbitmq
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -34,6 +35,77 @@ func TestAccPolicy(t *testing.T) {
 	})
 }
 
+func TestAccPolicy_definitionJson(t *testing.T) {
+	var policy rabbithole.Policy
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPolicyCheckDestroy(&policy),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPolicyConfig_definitionJson,
+				Check: resource.ComposeTestCheckFunc(
+					testAccPolicyCheck("rabbitmq_policy.test", &policy),
+					testAccPolicyCheckDefinitionTypes("test@test"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPolicy_invalidDefinition(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccPolicyConfig_invalidValue,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`"overflow" must be one of drop-head, reject-publish, reject-publish-dlx, got "drop-tail"`),
+			},
+			{
+				Config:      testAccPolicyConfig_invalidTarget,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Policy key "alternate-exchange" does not apply to queues, it applies to exchanges`),
+			},
+			{
+				Config:      testAccPolicyConfig_invalidApplyTo,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`expected policy.0.apply_to to be one of`),
+			},
+			{
+				Config:      testAccPolicyConfig_invalidPattern,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`is not a valid regular expression`),
+			},
+		},
+	})
+}
+
+func testAccPolicyCheckDefinitionTypes(id string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+		policyParts := strings.Split(id, "@")
+
+		policy, err := rmqc.GetPolicy(policyParts[1], policyParts[0])
+		if err != nil {
+			return fmt.Errorf("Error retrieving policy: %s", err)
+		}
+
+		if _, ok := policy.Definition["ha-params"].([]interface{}); !ok {
+			return fmt.Errorf("Expected ha-params to be a list, got %#v", policy.Definition["ha-params"])
+		}
+		if v, ok := policy.Definition["federation-upstream"].(string); !ok || v != "1234" {
+			return fmt.Errorf("Expected federation-upstream to be the string \"1234\", got %#v", policy.Definition["federation-upstream"])
+		}
+		if v, ok := policy.Definition["max-length"].(float64); !ok || v != 10000 {
+			return fmt.Errorf("Expected max-length to be the number 10000, got %#v", policy.Definition["max-length"])
+		}
+
+		return nil
+	}
+}
+
 func testAccPolicyCheck(rn string, policy *rabbithole.Policy) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -45,7 +117,7 @@ func testAccPolicyCheck(rn string, policy *rabbithole.Policy) resource.TestCheck
 			return fmt.Errorf("policy id not set")
 		}
 
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		policyParts := strings.Split(rs.Primary.ID, "@")
 
 		policies, err := rmqc.ListPolicies()
@@ -66,7 +138,7 @@ func testAccPolicyCheck(rn string, policy *rabbithole.Policy) resource.TestCheck
 
 func testAccPolicyCheckDestroy(policy *rabbithole.Policy) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 
 		policies, err := rmqc.ListPolicies()
 		if err != nil {
@@ -140,3 +212,90 @@ resource "rabbitmq_policy" "test" {
         }
     }
 }`
+
+const testAccPolicyConfig_definitionJson = `
+resource "rabbitmq_vhost" "test" {
+    name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+    user = "guest"
+    vhost = "${rabbitmq_vhost.test.name}"
+    permissions {
+        configure = ".*"
+        write = ".*"
+        read = ".*"
+    }
+}
+
+resource "rabbitmq_policy" "test" {
+    name = "test"
+    vhost = "${rabbitmq_permissions.guest.vhost}"
+    policy {
+        pattern = ".*"
+        priority = 0
+        apply_to = "all"
+        definition_json = jsonencode({
+            "ha-mode" = "nodes"
+            "ha-params" = ["a", "b", "c"]
+            "federation-upstream" = "1234"
+            "max-length" = 10000
+        })
+    }
+}`
+
+const testAccPolicyConfig_invalidValue = `
+resource "rabbitmq_policy" "test" {
+    name = "test"
+    vhost = "/"
+    policy {
+        pattern = ".*"
+        priority = 0
+        apply_to = "queues"
+        definition = {
+            overflow = "drop-tail"
+        }
+    }
+}`
+
+const testAccPolicyConfig_invalidTarget = `
+resource "rabbitmq_policy" "test" {
+    name = "test"
+    vhost = "/"
+    policy {
+        pattern = ".*"
+        priority = 0
+        apply_to = "queues"
+        definition = {
+            alternate-exchange = "unrouted"
+        }
+    }
+}`
+
+const testAccPolicyConfig_invalidApplyTo = `
+resource "rabbitmq_policy" "test" {
+    name = "test"
+    vhost = "/"
+    policy {
+        pattern = ".*"
+        priority = 0
+        apply_to = "queue"
+        definition = {
+            max-length = 10000
+        }
+    }
+}`
+
+const testAccPolicyConfig_invalidPattern = `
+resource "rabbitmq_policy" "test" {
+    name = "test"
+    vhost = "/"
+    policy {
+        pattern = "^(orders"
+        priority = 0
+        apply_to = "queues"
+        definition = {
+            max-length = 10000
+        }
+    }
+}`
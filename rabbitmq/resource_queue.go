@@ -1,10 +1,16 @@
 package rabbitmq
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"reflect"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -13,14 +19,20 @@ import (
 
 func resourceQueue() *schema.Resource {
 	return &schema.Resource{
-		Create:      CreateQueue,
-		Read:        ReadQueue,
-		Delete:      DeleteQueue,
-		Description: "The `rabbitmq_queue` resource creates and manages a queue in a RabbitMQ server.",
+		Create:        CreateQueue,
+		Read:          ReadQueue,
+		UpdateContext: UpdateQueue,
+		Delete:        DeleteQueue,
+		CustomizeDiff: customizeQueueDiff,
+		Description:   "The `rabbitmq_queue` resource creates and manages a queue in a RabbitMQ server.",
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -37,19 +49,81 @@ func resourceQueue() *schema.Resource {
 				Description: "The vhost to create the resource in.",
 			},
 
+			"recreate_strategy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "replace",
+				ValidateFunc: validation.StringInSlice([]string{"replace", "migrate"}, false),
+				Description:  "How the queue is recreated when its settings change. `replace` deletes the queue, and the messages it holds, before declaring it again. `migrate` moves the messages to a temporary queue with a dynamic shovel, recreates the queue and moves the messages and bindings back. Requires the shovel plugin for `migrate`.",
+			},
+
+			"force_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to delete the queue even if it still holds messages or has consumers. When `false`, destroying a non-empty or in-use queue fails.",
+			},
+
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the queue, e.g. `classic`, `quorum` or `stream`.",
+			},
+
+			"node": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The node hosting the queue, or its leader replica.",
+			},
+
+			"leader": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The node hosting the leader replica of a quorum queue or stream.",
+			},
+
+			"members": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The nodes hosting a replica of a quorum queue or stream.",
+			},
+
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the queue, e.g. `running`.",
+			},
+
+			"messages": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of messages in the queue when it was last read.",
+			},
+
+			"consumers": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of consumers of the queue when it was last read.",
+			},
+
+			"effective_policy": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the policy applied to the queue, if any.",
+			},
+
 			"settings": {
 				Type:        schema.TypeList,
 				Required:    true,
-				ForceNew:    true,
 				MaxItems:    1,
-				Description: "The settings for the queue.",
+				Description: "The settings for the queue. Changing any setting recreates the queue, see `recreate_strategy`.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"durable": {
 							Type:        schema.TypeBool,
 							Optional:    true,
 							Default:     false,
-							ForceNew:    true,
 							Description: "Whether the queue survives server restarts.",
 						},
 
@@ -57,7 +131,6 @@ func resourceQueue() *schema.Resource {
 							Type:        schema.TypeBool,
 							Optional:    true,
 							Default:     false,
-							ForceNew:    true,
 							Description: "Whether the queue is deleted when the number of consumers drops to zero.",
 						},
 
@@ -65,7 +138,6 @@ func resourceQueue() *schema.Resource {
 							Type:          schema.TypeMap,
 							Optional:      true,
 							ConflictsWith: []string{"settings.0.arguments_json"},
-							ForceNew:      true,
 							Description:   "Additional key/value settings for the queue. All values will be sent to RabbitMQ as a string. If you require non-string values, use `arguments_json`.",
 						},
 
@@ -75,9 +147,89 @@ func resourceQueue() *schema.Resource {
 							ValidateFunc:     validation.StringIsJSON,
 							ConflictsWith:    []string{"settings.0.arguments"},
 							DiffSuppressFunc: structure.SuppressJsonDiff,
-							ForceNew:         true,
 							Description:      "A nested JSON string which contains additional settings for the queue. This is useful for when the arguments contain non-string values.",
 						},
+
+						"message_ttl": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "How long (in milliseconds) a message published to the queue can live before it is discarded. Sets the `x-message-ttl` argument.",
+						},
+
+						"expires": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "How long (in milliseconds) the queue can be unused before it is automatically deleted. Sets the `x-expires` argument.",
+						},
+
+						"max_length": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "How many ready messages the queue can contain before it starts to drop them from its head. Sets the `x-max-length` argument.",
+						},
+
+						"max_length_bytes": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Total body size of ready messages the queue can contain before it starts to drop them from its head. Sets the `x-max-length-bytes` argument.",
+						},
+
+						"overflow": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"drop-head", "reject-publish", "reject-publish-dlx"}, false),
+							Description:  "The queue behaviour when the maximum length is reached. Valid values are `drop-head`, `reject-publish` and `reject-publish-dlx`. Sets the `x-overflow` argument.",
+						},
+
+						"dead_letter_exchange": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The exchange to which rejected or expired messages are republished. Sets the `x-dead-letter-exchange` argument.",
+						},
+
+						"dead_letter_routing_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The routing key used when messages are dead-lettered. Sets the `x-dead-letter-routing-key` argument.",
+						},
+
+						"dead_letter_strategy": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"at-most-once", "at-least-once"}, false),
+							Description:  "The dead lettering strategy of a quorum queue. Valid values are `at-most-once` and `at-least-once`. Sets the `x-dead-letter-strategy` argument.",
+						},
+
+						"delivery_limit": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(-1),
+							Description:  "How many times a message of a quorum queue can be redelivered before it is dropped or dead-lettered. `-1` disables the limit. Sets the `x-delivery-limit` argument.",
+						},
+
+						"single_active_consumer": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Whether only one consumer at a time consumes from the queue. Sets the `x-single-active-consumer` argument.",
+						},
+
+						"max_priority": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntBetween(1, 255),
+							Description:  "The maximum number of priority levels the queue supports. Sets the `x-max-priority` argument.",
+						},
+
+						"queue_leader_locator": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"client-local", "balanced"}, false),
+							Description:  "The rule used to place the leader of a quorum queue or stream. Valid values are `client-local` and `balanced`. Sets the `x-queue-leader-locator` argument.",
+						},
 					},
 				},
 			},
@@ -86,28 +238,14 @@ func resourceQueue() *schema.Resource {
 }
 
 func CreateQueue(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name := d.Get("name").(string)
 	vhost := d.Get("vhost").(string)
-	settingsList := d.Get("settings").([]interface{})
-
-	settingsMap, ok := settingsList[0].(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("Unable to parse settings")
-	}
-
-	// If arguments_json is used, unmarshal it into a generic interface
-	// and use it as the "arguments" key for the queue.
-	if v, ok := settingsMap["arguments_json"].(string); ok && v != "" {
-		var arguments map[string]interface{}
-		err := json.Unmarshal([]byte(v), &arguments)
-		if err != nil {
-			return err
-		}
 
-		delete(settingsMap, "arguments_json")
-		settingsMap["arguments"] = arguments
+	settingsMap, err := queueSettingsFromResourceData(d)
+	if err != nil {
+		return err
 	}
 
 	if err := declareQueue(rmqc, vhost, name, settingsMap); err != nil {
@@ -121,7 +259,7 @@ func CreateQueue(d *schema.ResourceData, meta interface{}) error {
 }
 
 func ReadQueue(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -138,10 +276,28 @@ func ReadQueue(d *schema.ResourceData, meta interface{}) error {
 	d.Set("name", queueSettings.Name)
 	d.Set("vhost", queueSettings.Vhost)
 
+	// recreate_strategy and force_destroy only live in Terraform, keep their defaults on import.
+	if _, ok := d.GetOk("recreate_strategy"); !ok {
+		d.Set("recreate_strategy", "replace")
+	}
+	d.Set("force_destroy", d.Get("force_destroy").(bool))
+
+	// Runtime attributes are computed only, so their changes never produce a diff.
+	d.Set("type", queueSettings.Type)
+	d.Set("node", queueSettings.Node)
+	d.Set("leader", queueSettings.Leader)
+	d.Set("members", queueSettings.Members)
+	d.Set("state", queueSettings.Status)
+	d.Set("messages", queueSettings.Messages)
+	d.Set("consumers", queueSettings.Consumers)
+	d.Set("effective_policy", queueSettings.Policy)
+
 	e := make(map[string]interface{})
 	e["durable"] = queueSettings.Durable
 	e["auto_delete"] = queueSettings.AutoDelete
 
+	arguments := splitQueueTypedArguments(d, queueSettings.Arguments, e)
+
 	// The user may have used either `arguments` or `arguments_json` to populate this originally.
 	// We need to preserve that decision here so that a subsequent Terraform plan for the
 	// same configuration wouldn't produce an errant diff that moves the value from one
@@ -150,14 +306,14 @@ func ReadQueue(d *schema.ResourceData, meta interface{}) error {
 	// `arguments` cannot receive any values other than a string (d.Set will fail), therefore any drift
 	// containing nonstring values AND the configuration originated from `arguments`,
 	// will now be encoded to `arguments_json`.
-	if _, ok := d.GetOk("settings.0.arguments_json"); ok || nonStringInArguments(queueSettings.Arguments) {
-		bytes, err := json.Marshal(queueSettings.Arguments)
+	if _, ok := d.GetOk("settings.0.arguments_json"); ok || nonStringInArguments(arguments) {
+		bytes, err := json.Marshal(arguments)
 		if err != nil {
 			return err
 		}
 		e["arguments_json"] = string(bytes)
 	} else {
-		e["arguments"] = queueSettings.Arguments
+		e["arguments"] = arguments
 	}
 
 	queue := make([]map[string]interface{}, 1)
@@ -166,8 +322,35 @@ func ReadQueue(d *schema.ResourceData, meta interface{}) error {
 	return d.Set("settings", queue)
 }
 
+func UpdateQueue(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	rmqc := meta.(*providerMeta).Client
+
+	name, vhost, err := parseResourceId(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+
+	// Settings only reach Update with the migrate strategy,
+	// customizeQueueDiff forces a replacement otherwise.
+	if d.HasChange("settings") {
+		settingsMap, err := queueSettingsFromResourceData(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		diags = migrateQueue(d, rmqc, vhost, name, settingsMap)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	return append(diags, diag.FromErr(ReadQueue(d, meta))...)
+}
+
 func DeleteQueue(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -176,9 +359,20 @@ func DeleteQueue(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[DEBUG] RabbitMQ: Attempting to delete queue for %s", d.Id())
 
-	resp, err := rmqc.DeleteQueue(vhost, name)
+	forceDestroy := d.Get("force_destroy").(bool)
+
+	var opts []rabbithole.QueueDeleteOptions
+	if !forceDestroy {
+		opts = append(opts, rabbithole.QueueDeleteOptions{IfEmpty: true, IfUnused: true})
+	}
+
+	resp, err := rmqc.DeleteQueue(vhost, name, opts...)
 	log.Printf("[DEBUG] RabbitMQ: Queue delete response: %#v", resp)
 	if err != nil {
+		var errorResponse rabbithole.ErrorResponse
+		if !forceDestroy && errors.As(err, &errorResponse) && errorResponse.StatusCode == 400 {
+			return queueNotDeletedError(rmqc, vhost, name, err)
+		}
 		return err
 	}
 
@@ -194,6 +388,82 @@ func DeleteQueue(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+// queueNotDeletedError explains why a conditional delete of the queue was refused.
+func queueNotDeletedError(rmqc *rabbithole.Client, vhost string, name string, cause error) error {
+	queue, err := rmqc.GetQueue(vhost, name)
+	if err != nil {
+		return fmt.Errorf("Error deleting RabbitMQ queue %s@%s, it is not empty or still in use. Set `force_destroy` to delete it anyway: %w", name, vhost, cause)
+	}
+
+	return fmt.Errorf("Error deleting RabbitMQ queue %s@%s, it holds %d messages and has %d consumers. Set `force_destroy` to delete it anyway: %w", name, vhost, queue.Messages, queue.Consumers, cause)
+}
+
+// queueSettingsFromResourceData returns the settings of the queue with
+// `arguments_json` and the typed arguments merged into "arguments".
+func queueSettingsFromResourceData(d *schema.ResourceData) (map[string]interface{}, error) {
+	settingsList := d.Get("settings").([]interface{})
+
+	settingsMap, ok := settingsList[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unable to parse settings")
+	}
+
+	// If arguments_json is used, unmarshal it into a generic interface
+	// and use it as the "arguments" key for the queue.
+	if v, ok := settingsMap["arguments_json"].(string); ok && v != "" {
+		var arguments map[string]interface{}
+		err := json.Unmarshal([]byte(v), &arguments)
+		if err != nil {
+			return nil, err
+		}
+
+		delete(settingsMap, "arguments_json")
+		settingsMap["arguments"] = arguments
+	}
+
+	arguments, err := mergeQueueTypedArguments(d, settingsMap)
+	if err != nil {
+		return nil, err
+	}
+	settingsMap["arguments"] = arguments
+
+	return settingsMap, nil
+}
+
+// customizeQueueDiff replaces the queue when its settings change,
+// unless the migrate strategy lets UpdateQueue recreate it in place.
+func customizeQueueDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || d.Get("recreate_strategy").(string) == "migrate" {
+		return nil
+	}
+
+	oldSettings, newSettings := d.GetChange("settings")
+	oldList := oldSettings.([]interface{})
+	newList := newSettings.([]interface{})
+	if len(oldList) == 0 || len(newList) == 0 {
+		return nil
+	}
+
+	oldMap, _ := oldList[0].(map[string]interface{})
+	newMap, _ := newList[0].(map[string]interface{})
+	for key, value := range newMap {
+		if reflect.DeepEqual(oldMap[key], value) {
+			continue
+		}
+
+		attribute := "settings.0." + key
+		if !d.HasChange(attribute) {
+			continue
+		}
+
+		if err := d.ForceNew(attribute); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func declareQueue(rmqc *rabbithole.Client, vhost string, name string, settingsMap map[string]interface{}) error {
 	queueSettings := rabbithole.QueueSettings{}
 
@@ -235,3 +505,130 @@ func nonStringInArguments(args map[string]interface{}) bool {
 	}
 	return false
 }
+
+// queueTypedArgument maps a typed attribute of the queue settings
+// onto the `x-` argument it is sent to RabbitMQ as.
+type queueTypedArgument struct {
+	attribute string
+	key       string
+	valueType schema.ValueType
+}
+
+var queueTypedArguments = []queueTypedArgument{
+	{"message_ttl", "x-message-ttl", schema.TypeInt},
+	{"expires", "x-expires", schema.TypeInt},
+	{"max_length", "x-max-length", schema.TypeInt},
+	{"max_length_bytes", "x-max-length-bytes", schema.TypeInt},
+	{"overflow", "x-overflow", schema.TypeString},
+	{"dead_letter_exchange", "x-dead-letter-exchange", schema.TypeString},
+	{"dead_letter_routing_key", "x-dead-letter-routing-key", schema.TypeString},
+	{"dead_letter_strategy", "x-dead-letter-strategy", schema.TypeString},
+	{"delivery_limit", "x-delivery-limit", schema.TypeInt},
+	{"single_active_consumer", "x-single-active-consumer", schema.TypeBool},
+	{"max_priority", "x-max-priority", schema.TypeInt},
+	{"queue_leader_locator", "x-queue-leader-locator", schema.TypeString},
+}
+
+// mergeQueueTypedArguments returns the generic arguments of settingsMap
+// extended with every typed argument set in the configuration.
+// Zero values are legitimate for most of these arguments (e.g. a TTL of 0),
+// so the raw configuration is used to tell an unset attribute from a zero one.
+func mergeQueueTypedArguments(d *schema.ResourceData, settingsMap map[string]interface{}) (map[string]interface{}, error) {
+	arguments := make(map[string]interface{})
+	if v, ok := settingsMap["arguments"].(map[string]interface{}); ok {
+		for key, value := range v {
+			arguments[key] = value
+		}
+	}
+
+	for _, arg := range queueTypedArguments {
+		value, ok := settingsMap[arg.attribute]
+		if !ok || !queueSettingIsSet(d, arg.attribute, value) {
+			continue
+		}
+
+		if _, ok := arguments[arg.key]; ok {
+			return nil, fmt.Errorf("`%s` conflicts with the `%s` key of the queue arguments", arg.attribute, arg.key)
+		}
+		arguments[arg.key] = value
+	}
+
+	return arguments, nil
+}
+
+// splitQueueTypedArguments moves the arguments that have a typed attribute
+// from serverArguments into settings and returns the remaining arguments.
+// Arguments the user manages through `arguments` or `arguments_json` are left
+// in place so that existing configurations don't produce a diff.
+func splitQueueTypedArguments(d *schema.ResourceData, serverArguments map[string]interface{}, settings map[string]interface{}) map[string]interface{} {
+	arguments := make(map[string]interface{}, len(serverArguments))
+	for key, value := range serverArguments {
+		arguments[key] = value
+	}
+
+	generic := configuredArguments(d)
+	for _, arg := range queueTypedArguments {
+		value, ok := arguments[arg.key]
+		if !ok {
+			continue
+		}
+
+		if _, ok := generic[arg.key]; ok {
+			continue
+		}
+
+		switch arg.valueType {
+		case schema.TypeInt:
+			if v, ok := value.(float64); ok && v == float64(int(v)) {
+				settings[arg.attribute] = int(v)
+				delete(arguments, arg.key)
+			}
+		case schema.TypeBool:
+			if v, ok := value.(bool); ok {
+				settings[arg.attribute] = v
+				delete(arguments, arg.key)
+			}
+		case schema.TypeString:
+			if v, ok := value.(string); ok {
+				settings[arg.attribute] = v
+				delete(arguments, arg.key)
+			}
+		}
+	}
+
+	return arguments
+}
+
+// queueSettingIsSet reports whether attribute was explicitly set in the
+// settings block. When no configuration is available (e.g. during import)
+// it falls back to checking value against its zero value.
+func queueSettingIsSet(d *schema.ResourceData, attribute string, value interface{}) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() || !config.Type().IsObjectType() || !config.Type().HasAttribute("settings") {
+		return !isZeroValue(value)
+	}
+
+	settings := config.GetAttr("settings")
+	if settings.IsNull() || !settings.IsKnown() || settings.LengthInt() == 0 {
+		return !isZeroValue(value)
+	}
+
+	setting := settings.Index(cty.NumberIntVal(0))
+	if setting.IsNull() || !setting.Type().HasAttribute(attribute) {
+		return false
+	}
+
+	return !setting.GetAttr(attribute).IsNull()
+}
+
+func isZeroValue(value interface{}) bool {
+	switch v := value.(type) {
+	case int:
+		return v == 0
+	case bool:
+		return !v
+	case string:
+		return v == ""
+	}
+	return value == nil
+}
@@ -1,11 +1,16 @@
 package rabbitmq
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -35,6 +40,33 @@ func TestAccQueue_basic(t *testing.T) {
 	})
 }
 
+func TestAccQueue_runtimeAttributes(t *testing.T) {
+	var queueInfo rabbithole.QueueInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccQueueCheckDestroy(&queueInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccQueueConfig_forceDestroy(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccQueueCheck("rabbitmq_queue.test", &queueInfo),
+					resource.TestCheckResourceAttr("rabbitmq_queue.test", "type", "classic"),
+					resource.TestCheckResourceAttrSet("rabbitmq_queue.test", "node"),
+					resource.TestCheckResourceAttr("rabbitmq_queue.test", "messages", "0"),
+					resource.TestCheckResourceAttr("rabbitmq_queue.test", "consumers", "0"),
+				),
+			},
+			{
+				// A message changes the runtime counters but must not produce a diff.
+				PreConfig: testAccQueuePublish(t, &queueInfo),
+				Config:    testAccQueueConfig_forceDestroy(true),
+				PlanOnly:  true,
+			},
+		},
+	})
+}
+
 func TestAccQueue_jsonArguments(t *testing.T) {
 	var queueInfo rabbithole.QueueInfo
 	js := `{"x-message-ttl": 5000,"foo": "bar","baz": 50}`
@@ -54,6 +86,118 @@ func TestAccQueue_jsonArguments(t *testing.T) {
 	})
 }
 
+func TestAccQueue_typedArguments(t *testing.T) {
+	var queueInfo rabbithole.QueueInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccQueueCheckDestroy(&queueInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccQueueConfig_typedArguments,
+				Check: resource.ComposeTestCheckFunc(
+					testAccQueueCheck("rabbitmq_queue.test", &queueInfo),
+					testAccQueueCheckArguments(&queueInfo, map[string]interface{}{
+						"x-message-ttl":             float64(0),
+						"x-max-length":              float64(1000),
+						"x-overflow":                "reject-publish",
+						"x-dead-letter-exchange":    "",
+						"x-dead-letter-routing-key": "test.dlq",
+						"x-single-active-consumer":  true,
+						"foo":                       "bar",
+					}),
+					resource.TestCheckResourceAttr("rabbitmq_queue.test", "settings.0.message_ttl", "0"),
+					resource.TestCheckResourceAttr("rabbitmq_queue.test", "settings.0.max_length", "1000"),
+					resource.TestCheckResourceAttr("rabbitmq_queue.test", "settings.0.overflow", "reject-publish"),
+					resource.TestCheckResourceAttr("rabbitmq_queue.test", "settings.0.dead_letter_routing_key", "test.dlq"),
+					resource.TestCheckResourceAttr("rabbitmq_queue.test", "settings.0.single_active_consumer", "true"),
+					resource.TestCheckResourceAttr("rabbitmq_queue.test", "settings.0.arguments.%", "1"),
+					resource.TestCheckResourceAttr("rabbitmq_queue.test", "settings.0.arguments.foo", "bar"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccQueue_migrate(t *testing.T) {
+	var queueInfo rabbithole.QueueInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccQueueCheckDestroy(&queueInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccQueueConfig_migrate(1000),
+				Check: resource.ComposeTestCheckFunc(
+					testAccQueueCheck("rabbitmq_queue.test", &queueInfo),
+					testAccQueueCheckArguments(&queueInfo, map[string]interface{}{
+						"x-max-length": float64(1000),
+					}),
+				),
+			},
+			{
+				// The migration must keep the messages of the queue.
+				PreConfig: func() {
+					testAccQueuePublish(t, &queueInfo)()
+					testAccQueuePublish(t, &queueInfo)()
+				},
+				Config: testAccQueueConfig_migrate(2000),
+				Check: resource.ComposeTestCheckFunc(
+					testAccQueueCheck("rabbitmq_queue.test", &queueInfo),
+					testAccQueueCheckArguments(&queueInfo, map[string]interface{}{
+						"x-max-length": float64(2000),
+					}),
+					testAccQueueCheckBinding(&queueInfo, "amq.direct", "test"),
+					testAccQueueCheckMessages(&queueInfo, 2),
+					testAccQueueCheckMissing(&queueInfo, "test.terraform-migration"),
+				),
+			},
+			{
+				// Settings rejected by the broker leave the queue unchanged.
+				Config:      strings.Replace(testAccQueueConfig_migrate(3000), "max_length = 3000", "max_length = 3000\n\t\targuments = {\n\t\t\tx-max-length-bytes = \"lots\"\n\t\t}", 1),
+				ExpectError: regexp.MustCompile("The new settings of queue test were rejected, the queue was not changed"),
+			},
+			{
+				Config: testAccQueueConfig_migrate(2000),
+				Check: resource.ComposeTestCheckFunc(
+					testAccQueueCheckArguments(&queueInfo, map[string]interface{}{
+						"x-max-length": float64(2000),
+					}),
+					testAccQueueCheckBinding(&queueInfo, "amq.direct", "test"),
+					testAccQueueCheckMessages(&queueInfo, 2),
+					testAccQueueCheckMissing(&queueInfo, "test.terraform-check"),
+					testAccQueueCheckMissing(&queueInfo, "test.terraform-migration"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccQueue_forceDestroy(t *testing.T) {
+	var queueInfo rabbithole.QueueInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccQueueCheckDestroy(&queueInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccQueueConfig_forceDestroy(false),
+				Check:  testAccQueueCheck("rabbitmq_queue.test", &queueInfo),
+			},
+			{
+				PreConfig:   testAccQueuePublish(t, &queueInfo),
+				Config:      testAccQueueConfig_forceDestroy(false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`it holds \d+ messages and has \d+ consumers`),
+			},
+			{
+				Config: testAccQueueConfig_forceDestroy(true),
+				Check:  resource.TestCheckResourceAttr("rabbitmq_queue.test", "force_destroy", "true"),
+			},
+		},
+	})
+}
+
 func testAccQueueCheck(rn string, queueInfo *rabbithole.QueueInfo) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -65,7 +209,7 @@ func testAccQueueCheck(rn string, queueInfo *rabbithole.QueueInfo) resource.Test
 			return fmt.Errorf("queue id not set")
 		}
 
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		queueParts := strings.Split(rs.Primary.ID, "@")
 
 		queues, err := rmqc.ListQueuesIn(queueParts[1])
@@ -110,9 +254,111 @@ func testAccQueueCheckJsonArguments(rn string, queueInfo *rabbithole.QueueInfo,
 	}
 }
 
+func testAccQueueCheckArguments(queueInfo *rabbithole.QueueInfo, expected map[string]interface{}) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if !reflect.DeepEqual(expected, queueInfo.Arguments) {
+			return fmt.Errorf("Queue arguments %#v do not match expected arguments %#v", queueInfo.Arguments, expected)
+		}
+
+		return nil
+	}
+}
+
+func testAccQueueCheckBinding(queueInfo *rabbithole.QueueInfo, source string, routingKey string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+
+		bindings, err := rmqc.ListQueueBindingsBetween(queueInfo.Vhost, source, queueInfo.Name)
+		if err != nil {
+			return fmt.Errorf("Error retrieving bindings: %s", err)
+		}
+
+		for _, binding := range bindings {
+			if binding.RoutingKey == routingKey {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Unable to find binding from %s to queue %s", source, queueInfo.Name)
+	}
+}
+
+// testAccQueueCheckMessages waits for the message count of the queue, which
+// the management API only refreshes every few seconds.
+func testAccQueueCheckMessages(queueInfo *rabbithole.QueueInfo, messages int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+
+		var count int
+		for i := 0; i < 15; i++ {
+			queue, err := rmqc.GetQueue(queueInfo.Vhost, queueInfo.Name)
+			if err != nil {
+				return fmt.Errorf("Error retrieving queue: %s", err)
+			}
+			count = queue.Messages
+			if count == messages {
+				return nil
+			}
+			time.Sleep(2 * time.Second)
+		}
+
+		return fmt.Errorf("Expected queue %s to have %d messages, got %d", queueInfo.Name, messages, count)
+	}
+}
+
+// testAccQueueCheckMissing checks that a queue does not exist in the vhost of queueInfo.
+func testAccQueueCheckMissing(queueInfo *rabbithole.QueueInfo, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+
+		if _, err := rmqc.GetQueue(queueInfo.Vhost, name); err == nil {
+			return fmt.Errorf("Queue %s still exists", name)
+		} else if !isNotFound(err) {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// testAccQueuePublish publishes a message to the queue through the default exchange.
+func testAccQueuePublish(t *testing.T, queueInfo *rabbithole.QueueInfo) func() {
+	return func() {
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+
+		body, err := json.Marshal(map[string]interface{}{
+			"properties":       map[string]interface{}{},
+			"routing_key":      queueInfo.Name,
+			"payload":          "test",
+			"payload_encoding": "string",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		endpoint := fmt.Sprintf("%s/api/exchanges/%s/amq.default/publish", rmqc.Endpoint, url.PathEscape(queueInfo.Vhost))
+		req, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.SetBasicAuth(rmqc.Username, rmqc.Password)
+		req.Header.Add("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 400 {
+			t.Fatalf("Error publishing message: %s", resp.Status)
+		}
+	}
+}
+
 func testAccQueueCheckDestroy(queueInfo *rabbithole.QueueInfo) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 
 		queues, err := rmqc.ListQueuesIn(queueInfo.Vhost)
 		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "not found") {
@@ -209,3 +455,96 @@ resource "rabbitmq_queue" "test" {
 	}
 }`, j)
 }
+
+const testAccQueueConfig_typedArguments = `
+resource "rabbitmq_vhost" "test" {
+	name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+	user = "guest"
+	vhost = "${rabbitmq_vhost.test.name}"
+	permissions {
+		configure = ".*"
+		write = ".*"
+		read = ".*"
+	}
+}
+
+resource "rabbitmq_queue" "test" {
+	name = "test"
+	vhost = "${rabbitmq_permissions.guest.vhost}"
+	settings {
+		durable = true
+		message_ttl = 0
+		max_length = 1000
+		overflow = "reject-publish"
+		dead_letter_exchange = ""
+		dead_letter_routing_key = "test.dlq"
+		single_active_consumer = true
+		arguments = {
+			foo = "bar"
+		}
+	}
+}`
+
+func testAccQueueConfig_migrate(maxLength int) string {
+	return fmt.Sprintf(`
+resource "rabbitmq_vhost" "test" {
+	name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+	user = "guest"
+	vhost = "${rabbitmq_vhost.test.name}"
+	permissions {
+		configure = ".*"
+		write = ".*"
+		read = ".*"
+	}
+}
+
+resource "rabbitmq_queue" "test" {
+	name = "test"
+	vhost = "${rabbitmq_permissions.guest.vhost}"
+	recreate_strategy = "migrate"
+	settings {
+		durable = true
+		max_length = %d
+	}
+}
+
+resource "rabbitmq_binding" "test" {
+	source = "amq.direct"
+	vhost = "${rabbitmq_vhost.test.name}"
+	destination = "${rabbitmq_queue.test.name}"
+	destination_type = "queue"
+	routing_key = "test"
+}`, maxLength)
+}
+
+func testAccQueueConfig_forceDestroy(forceDestroy bool) string {
+	return fmt.Sprintf(`
+resource "rabbitmq_vhost" "test" {
+	name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+	user = "guest"
+	vhost = "${rabbitmq_vhost.test.name}"
+	permissions {
+		configure = ".*"
+		write = ".*"
+		read = ".*"
+	}
+}
+
+resource "rabbitmq_queue" "test" {
+	name = "test"
+	vhost = "${rabbitmq_permissions.guest.vhost}"
+	force_destroy = %t
+	settings {
+		durable = true
+	}
+}`, forceDestroy)
+}
@@ -236,7 +236,7 @@ func resourceShovel() *schema.Resource {
 }
 
 func CreateShovel(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	vhost := d.Get("vhost").(string)
 	shovelName := d.Get("name").(string)
@@ -264,7 +264,7 @@ func CreateShovel(d *schema.ResourceData, meta interface{}) error {
 }
 
 func ReadShovel(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -317,7 +317,7 @@ func ReadShovel(d *schema.ResourceData, meta interface{}) error {
 }
 
 func UpdateShovel(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -346,7 +346,7 @@ func UpdateShovel(d *schema.ResourceData, meta interface{}) error {
 }
 
 func DeleteShovel(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	name, vhost, err := parseResourceId(d)
 	if err != nil {
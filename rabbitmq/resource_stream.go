@@ -0,0 +1,564 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+)
+
+// Stream arguments can't be changed once the stream is declared, but its
+// retention can also be set by a policy. With the `policy` retention strategy
+// the retention settings of a rabbitmq_stream are kept in a policy dedicated
+// to the stream, so that they can be updated in place. As only one policy
+// applies to a stream, that policy replaces the other policies matching the
+// stream, or is shadowed by them. The `arguments` strategy declares them as
+// arguments of the stream instead, which recreates it when they change.
+//
+// The retention attributes hold the settings of the resource, read from its
+// own policy or arguments, while the `effective_*` attributes hold the ones
+// applied once other policies and operator policies are taken into account.
+
+const (
+	streamRetentionStrategyPolicy    = "policy"
+	streamRetentionStrategyArguments = "arguments"
+)
+
+var streamMaxAgeRegexp = regexp.MustCompile(`^[1-9][0-9]*[YMDhms]$`)
+
+// streamRetentionKeys maps the retention attributes of a stream
+// to the policy keys they are applied with.
+var streamRetentionKeys = map[string]string{
+	"max_age":                       "max-age",
+	"max_length_bytes":              "max-length-bytes",
+	"stream_max_segment_size_bytes": "stream-max-segment-size-bytes",
+}
+
+func resourceStream() *schema.Resource {
+	return &schema.Resource{
+		Create:      CreateStream,
+		Read:        ReadStream,
+		Update:      UpdateStream,
+		Delete:      DeleteStream,
+		Description: "The `rabbitmq_stream` resource creates and manages a stream in a RabbitMQ server.",
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: customizeStreamRetentionDiff,
+
+		Schema: withStreamRetentionSchema("<name>-stream-retention", map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the stream.",
+			},
+
+			"vhost": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "/",
+				ForceNew:    true,
+				Description: "The vhost to create the resource in.",
+			},
+
+			"initial_cluster_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The number of nodes the stream is initially replicated to. Sets the `x-initial-cluster-size` argument.",
+			},
+
+			"leader_locator": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"client-local", "balanced"}, false),
+				Description:  "The rule used to place the leader of the stream. Valid values are `client-local` and `balanced`. Sets the `x-queue-leader-locator` argument.",
+			},
+
+			"retention_policy": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the policy holding the retention settings of the stream, if it exists. As only one policy applies to a stream, other policies matching the stream with a lower priority are ignored while retention settings are set.",
+			},
+
+			"effective_policy": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the policy applied to the stream, if any. When it is not `retention_policy`, the retention settings are shadowed by this policy.",
+			},
+		}),
+	}
+}
+
+// withStreamRetentionSchema adds the retention attributes of a stream to s.
+func withStreamRetentionSchema(defaultPolicyName string, s map[string]*schema.Schema) map[string]*schema.Schema {
+	s["retention_strategy"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      streamRetentionStrategyPolicy,
+		ForceNew:     true,
+		ValidateFunc: validation.StringInSlice([]string{streamRetentionStrategyPolicy, streamRetentionStrategyArguments}, false),
+		Description:  "How the retention settings are applied. `policy` applies them with a dedicated policy, so that they are updated in place, but as only one policy applies to a stream that policy replaces the other policies matching the stream while it takes precedence, and is shadowed by the ones with a higher priority. `arguments` declares them as `x-max-age`, `x-max-length-bytes` and `x-stream-max-segment-size-bytes` arguments, which leaves policies alone but recreates the stream when they change.",
+	}
+
+	s["retention_policy_name"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ValidateFunc: validation.StringIsNotEmpty,
+		Description:  fmt.Sprintf("The name of the policy holding the retention settings with the `policy` retention strategy. Defaults to `%s`.", defaultPolicyName),
+	}
+
+	s["max_age"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateStreamMaxAge,
+		Description:  "How long messages are retained, as a number followed by a unit: `Y`, `M`, `D`, `h`, `m` or `s`, e.g. `7D`.",
+	}
+
+	s["max_length_bytes"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntAtLeast(1),
+		Description:  "The total size of the stream in bytes, beyond which its oldest segments are discarded.",
+	}
+
+	s["stream_max_segment_size_bytes"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntAtLeast(1),
+		Description:  "The size in bytes of the segment files of the stream. Retention is applied a whole segment at a time.",
+	}
+
+	s["effective_max_age"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The `max-age` applied to the streams. It differs from `max_age` when another policy shadows the retention settings or sets it.",
+	}
+
+	s["effective_max_length_bytes"] = &schema.Schema{
+		Type:        schema.TypeInt,
+		Computed:    true,
+		Description: "The `max-length-bytes` applied to the streams. It differs from `max_length_bytes` when another policy shadows the retention settings or sets it, or an operator policy caps it.",
+	}
+
+	s["effective_stream_max_segment_size_bytes"] = &schema.Schema{
+		Type:        schema.TypeInt,
+		Computed:    true,
+		Description: "The `stream-max-segment-size-bytes` applied to the streams. It differs from `stream_max_segment_size_bytes` when another policy shadows the retention settings or sets it.",
+	}
+
+	s["retention_policy_priority"] = &schema.Schema{
+		Type:        schema.TypeInt,
+		Optional:    true,
+		Default:     0,
+		Description: "The priority of the policy holding the retention settings with the `policy` retention strategy. Other policies matching the stream with a higher priority shadow the retention settings.",
+	}
+
+	return s
+}
+
+func CreateStream(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	name := d.Get("name").(string)
+	vhost := d.Get("vhost").(string)
+
+	arguments := map[string]interface{}{}
+	if v, ok := d.GetOk("initial_cluster_size"); ok {
+		arguments["x-initial-cluster-size"] = v.(int)
+	}
+	if v, ok := d.GetOk("leader_locator"); ok {
+		arguments["x-queue-leader-locator"] = v.(string)
+	}
+	addStreamRetentionArguments(d, arguments)
+
+	if err := declareStream(rmqc, vhost, name, arguments); err != nil {
+		return err
+	}
+
+	id := fmt.Sprintf("%s@%s", name, vhost)
+	d.SetId(id)
+
+	if policyName := streamRetentionPolicyName(d, defaultStreamRetentionPolicyName(name)); policyName != "" {
+		pattern := fmt.Sprintf("^%s$", regexp.QuoteMeta(name))
+		if err := applyStreamRetentionPolicy(d, rmqc, vhost, policyName, pattern); err != nil {
+			return err
+		}
+	}
+
+	return ReadStream(d, meta)
+}
+
+func ReadStream(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	name, vhost, err := parseResourceId(d)
+	if err != nil {
+		return err
+	}
+
+	stream, err := rmqc.GetQueue(vhost, name)
+	if err != nil {
+		return checkDeleted(d, err)
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: Stream retrieved for %s: %#v", d.Id(), stream)
+
+	if stream.Type != "" && stream.Type != "stream" {
+		return fmt.Errorf("Queue %s is a %s queue, not a stream", d.Id(), stream.Type)
+	}
+
+	d.Set("name", stream.Name)
+	d.Set("vhost", stream.Vhost)
+
+	if v, ok := stream.Arguments["x-initial-cluster-size"].(float64); ok {
+		d.Set("initial_cluster_size", int(v))
+	} else {
+		d.Set("initial_cluster_size", 0)
+	}
+	if v, ok := stream.Arguments["x-queue-leader-locator"].(string); ok {
+		d.Set("leader_locator", v)
+	} else {
+		d.Set("leader_locator", "")
+	}
+
+	var object policyObjectInfo
+	path := fmt.Sprintf("queues/%s/%s", url.PathEscape(vhost), url.PathEscape(name))
+	if _, err := managementRequest(rmqc, http.MethodGet, path, nil, &object); err != nil {
+		return err
+	}
+	d.Set("effective_policy", object.Policy)
+
+	return readStreamRetention(d, rmqc, vhost, defaultStreamRetentionPolicyName(name), stream.Arguments, []map[string]interface{}{object.EffectivePolicyDefinition})
+}
+
+func UpdateStream(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	name, vhost, err := parseResourceId(d)
+	if err != nil {
+		return err
+	}
+
+	if policyName := streamRetentionPolicyName(d, defaultStreamRetentionPolicyName(name)); policyName != "" && d.HasChanges(streamRetentionPolicyAttributes...) {
+		pattern := fmt.Sprintf("^%s$", regexp.QuoteMeta(name))
+		if err := applyStreamRetentionPolicy(d, rmqc, vhost, policyName, pattern); err != nil {
+			return err
+		}
+	}
+
+	return ReadStream(d, meta)
+}
+
+func DeleteStream(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	name, vhost, err := parseResourceId(d)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: Attempting to delete stream for %s", d.Id())
+
+	resp, err := rmqc.DeleteQueue(vhost, name)
+	log.Printf("[DEBUG] RabbitMQ: Stream delete response: %#v", resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 && resp.StatusCode != 404 {
+		return fmt.Errorf("Error deleting RabbitMQ stream: %s", resp.Status)
+	}
+
+	// Only the retention policy managed by the resource is deleted.
+	if policyName := d.Get("retention_policy").(string); policyName != "" {
+		return deleteStreamRetentionPolicy(rmqc, vhost, policyName)
+	}
+
+	return nil
+}
+
+func declareStream(rmqc *rabbithole.Client, vhost string, name string, arguments map[string]interface{}) error {
+	// Streams are always durable and never auto-deleted.
+	queueSettings := rabbithole.QueueSettings{
+		Type:      "stream",
+		Durable:   true,
+		Arguments: arguments,
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: Attempting to declare stream for %s@%s: %#v", name, vhost, queueSettings)
+
+	resp, err := rmqc.DeclareQueue(vhost, name, queueSettings)
+	log.Printf("[DEBUG] RabbitMQ: Stream declare response: %#v", resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("Error declaring RabbitMQ stream: %s", resp.Status)
+	}
+
+	return nil
+}
+
+func defaultStreamRetentionPolicyName(name string) string {
+	return fmt.Sprintf("%s-stream-retention", name)
+}
+
+// streamRetentionPolicyAttributes are the attributes whose changes update
+// the retention policy.
+var streamRetentionPolicyAttributes = []string{"max_age", "max_length_bytes", "stream_max_segment_size_bytes", "retention_policy_name", "retention_policy_priority"}
+
+// streamRetentionPolicyName returns the name of the retention policy set in d,
+// or an empty string with the arguments retention strategy.
+func streamRetentionPolicyName(d *schema.ResourceData, defaultName string) string {
+	if d.Get("retention_strategy").(string) == streamRetentionStrategyArguments {
+		return ""
+	}
+
+	if v, ok := d.GetOk("retention_policy_name"); ok {
+		return v.(string)
+	}
+	return defaultName
+}
+
+// addStreamRetentionArguments adds the retention attributes set in d to the
+// arguments of a stream with the arguments retention strategy.
+func addStreamRetentionArguments(d *schema.ResourceData, arguments map[string]interface{}) {
+	if d.Get("retention_strategy").(string) != streamRetentionStrategyArguments {
+		return
+	}
+
+	for attribute, value := range streamRetentionFromResourceData(d) {
+		arguments["x-"+streamRetentionKeys[attribute]] = value
+	}
+}
+
+// customizeStreamRetentionDiff recreates the stream, or the partitions of a
+// super stream, when retention settings held by arguments change.
+func customizeStreamRetentionDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() == "" || diff.Get("retention_strategy").(string) != streamRetentionStrategyArguments {
+		return nil
+	}
+
+	for attribute := range streamRetentionKeys {
+		if diff.HasChange(attribute) {
+			if err := diff.ForceNew(attribute); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// readStreamRetention sets the retention attributes of d from the arguments
+// of the stream, or from the retention policy managed by the resource, and
+// the effective retention attributes from the effective policy definitions
+// of the streams.
+func readStreamRetention(d *schema.ResourceData, rmqc *rabbithole.Client, vhost string, defaultPolicyName string, arguments map[string]interface{}, effectiveDefinitions []map[string]interface{}) error {
+	strategy := d.Get("retention_strategy").(string)
+	importing := strategy == ""
+	if importing {
+		// The strategy only lives in Terraform, infer it on import.
+		strategy = streamRetentionStrategyPolicy
+		for _, key := range streamRetentionKeys {
+			if _, ok := arguments["x-"+key]; ok {
+				strategy = streamRetentionStrategyArguments
+			}
+		}
+		d.Set("retention_strategy", strategy)
+	}
+
+	configured := make(map[string]interface{})
+
+	if strategy == streamRetentionStrategyArguments {
+		for _, key := range streamRetentionKeys {
+			configured[key] = arguments["x-"+key]
+		}
+		d.Set("retention_policy", "")
+		d.Set("retention_policy_name", d.Get("retention_policy_name").(string))
+		d.Set("retention_policy_priority", d.Get("retention_policy_priority").(int))
+	} else {
+		policyName := streamRetentionPolicyName(d, defaultPolicyName)
+		d.Set("retention_policy_name", policyName)
+
+		// A policy of the same name which the resource does not manage,
+		// e.g. after Create refused to replace it, is not read.
+		var policy *rabbithole.Policy
+		if importing || d.Get("retention_policy").(string) == policyName {
+			var err error
+			if policy, err = rmqc.GetPolicy(vhost, policyName); err != nil {
+				if !isNotFound(err) {
+					return err
+				}
+				policy = nil
+			}
+		}
+
+		if policy != nil {
+			for _, key := range streamRetentionKeys {
+				configured[key] = policy.Definition[key]
+			}
+			d.Set("retention_policy", policyName)
+			d.Set("retention_policy_priority", policy.Priority)
+		} else {
+			d.Set("retention_policy", "")
+			d.Set("retention_policy_priority", d.Get("retention_policy_priority").(int))
+		}
+	}
+
+	for attribute, key := range streamRetentionKeys {
+		d.Set(attribute, streamRetentionValue(configured[key]))
+		d.Set("effective_"+attribute, streamRetentionValue(effectiveStreamRetention(configured[key], key, arguments, effectiveDefinitions)))
+	}
+
+	return nil
+}
+
+// effectiveStreamRetention returns the value of a retention key applied to
+// the streams, which is the value of their effective policy definition or
+// else of their argument: value when all streams have it, or else the first
+// value that differs from it.
+func effectiveStreamRetention(value interface{}, key string, arguments map[string]interface{}, effectiveDefinitions []map[string]interface{}) interface{} {
+	for _, definition := range effectiveDefinitions {
+		effective, ok := definition[key]
+		if !ok {
+			effective = arguments["x-"+key]
+		}
+		if !reflect.DeepEqual(effective, value) {
+			return effective
+		}
+	}
+	return value
+}
+
+// streamRetentionValue converts a retention value read from the API
+// to the value of its attribute.
+func streamRetentionValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return int(v)
+	}
+	return nil
+}
+
+// streamRetentionFromResourceData returns the retention attributes set in d.
+func streamRetentionFromResourceData(d *schema.ResourceData) map[string]interface{} {
+	retention := make(map[string]interface{})
+	for attribute := range streamRetentionKeys {
+		if v, ok := d.GetOk(attribute); ok {
+			retention[attribute] = v
+		}
+	}
+	return retention
+}
+
+// applyStreamRetentionPolicy applies the retention attributes set in d to
+// the streams matching pattern with the policy policyName, or deletes the
+// policy when no retention is set, and records the policy managed by the
+// resource in `retention_policy`. It fails rather than replace a policy it
+// does not manage.
+func applyStreamRetentionPolicy(d *schema.ResourceData, rmqc *rabbithole.Client, vhost string, policyName string, pattern string) error {
+	managed := d.Get("retention_policy").(string)
+	retention := streamRetentionFromResourceData(d)
+
+	if len(retention) > 0 && managed != policyName {
+		if err := checkStreamRetentionPolicyUnused(rmqc, vhost, policyName); err != nil {
+			return err
+		}
+	}
+
+	// The previous policy is deleted when its name changes or no retention is set.
+	if managed != "" && (managed != policyName || len(retention) == 0) {
+		if err := deleteStreamRetentionPolicy(rmqc, vhost, managed); err != nil {
+			return err
+		}
+		d.Set("retention_policy", "")
+	}
+
+	if len(retention) == 0 {
+		return nil
+	}
+
+	definition := rabbithole.PolicyDefinition{}
+	for attribute, value := range retention {
+		definition[streamRetentionKeys[attribute]] = value
+	}
+
+	policy := rabbithole.Policy{
+		Vhost:      vhost,
+		Name:       policyName,
+		Pattern:    pattern,
+		ApplyTo:    "queues",
+		Priority:   d.Get("retention_policy_priority").(int),
+		Definition: definition,
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: Attempting to declare stream retention policy for %s@%s: %#v", policyName, vhost, policy)
+
+	resp, err := rmqc.PutPolicy(vhost, policyName, policy)
+	log.Printf("[DEBUG] RabbitMQ: Policy declare response: %#v", resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("Error declaring RabbitMQ stream retention policy: %s", resp.Status)
+	}
+
+	d.Set("retention_policy", policyName)
+
+	return nil
+}
+
+// checkStreamRetentionPolicyUnused returns an error when a policy named
+// policyName exists, as it is not managed by the resource.
+func checkStreamRetentionPolicyUnused(rmqc *rabbithole.Client, vhost string, policyName string) error {
+	_, err := rmqc.GetPolicy(vhost, policyName)
+	if err == nil {
+		return fmt.Errorf("The policy %s already exists in vhost %s and is not managed by this resource. Set retention_policy_name to the name of another policy, or use the arguments retention strategy", policyName, vhost)
+	}
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func deleteStreamRetentionPolicy(rmqc *rabbithole.Client, vhost string, policyName string) error {
+	log.Printf("[DEBUG] RabbitMQ: Attempting to delete stream retention policy %s@%s", policyName, vhost)
+
+	resp, err := rmqc.DeletePolicy(vhost, policyName)
+	log.Printf("[DEBUG] RabbitMQ: Policy delete response: %#v", resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 && resp.StatusCode != 404 {
+		return fmt.Errorf("Error deleting RabbitMQ stream retention policy: %s", resp.Status)
+	}
+
+	return nil
+}
+
+func validateStreamMaxAge(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if !streamMaxAgeRegexp.MatchString(value) {
+		errors = append(errors, fmt.Errorf("%q must be a positive number followed by one of the units Y, M, D, h, m or s, e.g. 7D, got: %s", k, value))
+	}
+
+	return
+}
@@ -0,0 +1,457 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccStream_basic(t *testing.T) {
+	var streamInfo rabbithole.QueueInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamCheckDestroy(&streamInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStreamConfig("7D", 20000000),
+				Check: resource.ComposeTestCheckFunc(
+					testAccStreamCheck("rabbitmq_stream.test", &streamInfo),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "max_age", "7D"),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "max_length_bytes", "20000000"),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "initial_cluster_size", "1"),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "retention_policy", "test-stream-retention"),
+				),
+			},
+			{
+				// Retention changes are applied in place.
+				Config: testAccStreamConfig("12h", 10000000),
+				Check: resource.ComposeTestCheckFunc(
+					testAccStreamCheckSame("rabbitmq_stream.test", &streamInfo),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "max_age", "12h"),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "max_length_bytes", "10000000"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStream_retentionArguments(t *testing.T) {
+	var streamInfo rabbithole.QueueInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamCheckDestroy(&streamInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStreamConfig_retentionArguments("7D"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccStreamCheck("rabbitmq_stream.test", &streamInfo),
+					testAccStreamCheckArgument(&streamInfo, "x-max-age", "7D"),
+					testAccStreamCheckNoPolicy(&streamInfo, "test-stream-retention"),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "max_age", "7D"),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "retention_policy", ""),
+				),
+			},
+			{
+				ResourceName:      "rabbitmq_stream.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// Arguments can't be changed, the stream is recreated.
+				Config: testAccStreamConfig_retentionArguments("12h"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccStreamCheck("rabbitmq_stream.test", &streamInfo),
+					testAccStreamCheckArgument(&streamInfo, "x-max-age", "12h"),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "max_age", "12h"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStream_shadowedRetention(t *testing.T) {
+	var streamInfo rabbithole.QueueInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamCheckDestroy(&streamInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStreamConfig_retentionPolicy,
+				Check: resource.ComposeTestCheckFunc(
+					testAccStreamCheck("rabbitmq_stream.test", &streamInfo),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "retention_policy", "test-retention"),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "retention_policy_priority", "5"),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "effective_policy", "test-retention"),
+				),
+			},
+			{
+				// A policy with a higher priority shadows the retention settings,
+				// which only changes the effective ones.
+				PreConfig: testAccStreamPutPolicy(t, &streamInfo, "override", 10),
+				Config:    testAccStreamConfig_retentionPolicy,
+				PlanOnly:  true,
+			},
+			{
+				Config: testAccStreamConfig_retentionPolicy,
+				Check: resource.ComposeTestCheckFunc(
+					testAccStreamCheckSame("rabbitmq_stream.test", &streamInfo),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "max_age", "7D"),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "effective_max_age", "1D"),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "effective_policy", "override"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStream_cappedRetention(t *testing.T) {
+	var streamInfo rabbithole.QueueInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamCheckDestroy(&streamInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStreamConfig("7D", 20000000),
+				Check:  testAccStreamCheck("rabbitmq_stream.test", &streamInfo),
+			},
+			{
+				// An operator policy caps the max-length-bytes of the stream.
+				PreConfig: testAccStreamPutOperatorPolicy(t, streamInfo.Vhost, "^test$", 1000000),
+				Config:    testAccStreamConfig("7D", 20000000),
+				PlanOnly:  true,
+			},
+			{
+				Config: testAccStreamConfig("7D", 20000000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "max_length_bytes", "20000000"),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "effective_max_length_bytes", "1000000"),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "effective_max_age", "7D"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStream_existingRetentionPolicy(t *testing.T) {
+	var streamInfo rabbithole.QueueInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccStreamCheckDestroy(&streamInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStreamConfig_retentionPolicy,
+				Check:  testAccStreamCheck("rabbitmq_stream.test", &streamInfo),
+			},
+			{
+				// The policy is not replaced, nor deleted with the stream.
+				PreConfig:   testAccStreamPutPolicy(t, &streamInfo, "other-retention", 0),
+				Config:      strings.Replace(testAccStreamConfig_retentionPolicy, "test-retention", "other-retention", 1),
+				ExpectError: regexp.MustCompile("policy other-retention already exists in vhost test and is not managed by this resource"),
+			},
+			{
+				Config: testAccStreamConfig_retentionPolicy,
+				Check: resource.ComposeTestCheckFunc(
+					testAccStreamCheckSame("rabbitmq_stream.test", &streamInfo),
+					resource.TestCheckResourceAttr("rabbitmq_stream.test", "retention_policy", "test-retention"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccStream_invalidMaxAge(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccStreamConfig("7 days", 20000000),
+				ExpectError: regexp.MustCompile("must be a positive number followed by one of the units"),
+			},
+		},
+	})
+}
+
+func testAccStreamCheck(rn string, streamInfo *rabbithole.QueueInfo) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("stream id not set")
+		}
+
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+		streamParts := strings.Split(rs.Primary.ID, "@")
+
+		stream, err := rmqc.GetQueue(streamParts[1], streamParts[0])
+		if err != nil {
+			return fmt.Errorf("Error retrieving stream: %s", err)
+		}
+
+		if stream.Type != "stream" {
+			return fmt.Errorf("Queue %s is a %s queue, not a stream", rs.Primary.ID, stream.Type)
+		}
+
+		*streamInfo = rabbithole.QueueInfo(*stream)
+		return nil
+	}
+}
+
+// testAccStreamCheckSame checks that the stream was not recreated since it was last checked.
+func testAccStreamCheckSame(rn string, streamInfo *rabbithole.QueueInfo) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		previous := *streamInfo
+		if err := testAccStreamCheck(rn, streamInfo)(s); err != nil {
+			return err
+		}
+
+		if previous.Leader != streamInfo.Leader || !stringSlicesEqual(previous.Members, streamInfo.Members) {
+			return fmt.Errorf("Stream %s was recreated", rn)
+		}
+
+		return nil
+	}
+}
+
+func testAccStreamCheckArgument(streamInfo *rabbithole.QueueInfo, key string, value interface{}) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if v := streamInfo.Arguments[key]; v != value {
+			return fmt.Errorf("Expected argument %s of stream %s to be %v, got %v", key, streamInfo.Name, value, v)
+		}
+		return nil
+	}
+}
+
+func testAccStreamCheckNoPolicy(streamInfo *rabbithole.QueueInfo, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+
+		if _, err := rmqc.GetPolicy(streamInfo.Vhost, name); err == nil {
+			return fmt.Errorf("Policy %s@%s exists", name, streamInfo.Vhost)
+		}
+		return nil
+	}
+}
+
+// testAccStreamPutPolicy declares a policy setting the max-age of the stream.
+func testAccStreamPutPolicy(t *testing.T, streamInfo *rabbithole.QueueInfo, name string, priority int) func() {
+	return func() {
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+
+		policy := rabbithole.Policy{
+			Pattern:    fmt.Sprintf("^%s$", streamInfo.Name),
+			ApplyTo:    "queues",
+			Priority:   priority,
+			Definition: rabbithole.PolicyDefinition{"max-age": "1D"},
+		}
+		if _, err := rmqc.PutPolicy(streamInfo.Vhost, name, policy); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// testAccStreamPutOperatorPolicy declares an operator policy capping the
+// max-length-bytes of the streams matching pattern.
+func testAccStreamPutOperatorPolicy(t *testing.T, vhost string, pattern string, maxLengthBytes int) func() {
+	return func() {
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+
+		policy := rabbithole.OperatorPolicy{
+			Pattern:    pattern,
+			ApplyTo:    "queues",
+			Definition: rabbithole.PolicyDefinition{"max-length-bytes": maxLengthBytes},
+		}
+		if _, err := rmqc.PutOperatorPolicy(vhost, "cap", policy); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	return strings.Join(a, ",") == strings.Join(b, ",")
+}
+
+func testAccStreamCheckDestroy(streamInfo *rabbithole.QueueInfo) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+
+		queues, err := rmqc.ListQueuesIn(streamInfo.Vhost)
+		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "not found") {
+			return fmt.Errorf("Error retrieving queues: %s", err)
+		}
+
+		for _, queue := range queues {
+			if queue.Name == streamInfo.Name && queue.Vhost == streamInfo.Vhost {
+				return fmt.Errorf("Stream %s@%s still exist", streamInfo.Name, streamInfo.Vhost)
+			}
+		}
+
+		if _, err := rmqc.GetPolicy(streamInfo.Vhost, defaultStreamRetentionPolicyName(streamInfo.Name)); err == nil {
+			return fmt.Errorf("Retention policy of stream %s@%s still exist", streamInfo.Name, streamInfo.Vhost)
+		}
+
+		return nil
+	}
+}
+
+func testAccStreamConfig(maxAge string, maxLengthBytes int) string {
+	return fmt.Sprintf(`
+resource "rabbitmq_vhost" "test" {
+	name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+	user = "guest"
+	vhost = "${rabbitmq_vhost.test.name}"
+	permissions {
+		configure = ".*"
+		write = ".*"
+		read = ".*"
+	}
+}
+
+resource "rabbitmq_stream" "test" {
+	name = "test"
+	vhost = "${rabbitmq_permissions.guest.vhost}"
+	initial_cluster_size = 1
+	max_age = "%s"
+	max_length_bytes = %d
+}`, maxAge, maxLengthBytes)
+}
+
+func testAccStreamConfig_retentionArguments(maxAge string) string {
+	return fmt.Sprintf(`
+resource "rabbitmq_vhost" "test" {
+	name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+	user = "guest"
+	vhost = "${rabbitmq_vhost.test.name}"
+	permissions {
+		configure = ".*"
+		write = ".*"
+		read = ".*"
+	}
+}
+
+resource "rabbitmq_stream" "test" {
+	name = "test"
+	vhost = "${rabbitmq_permissions.guest.vhost}"
+	retention_strategy = "arguments"
+	max_age = "%s"
+}`, maxAge)
+}
+
+const testAccStreamConfig_retentionPolicy = `
+resource "rabbitmq_vhost" "test" {
+	name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+	user = "guest"
+	vhost = "${rabbitmq_vhost.test.name}"
+	permissions {
+		configure = ".*"
+		write = ".*"
+		read = ".*"
+	}
+}
+
+resource "rabbitmq_stream" "test" {
+	name = "test"
+	vhost = "${rabbitmq_permissions.guest.vhost}"
+	max_age = "7D"
+	retention_policy_name = "test-retention"
+	retention_policy_priority = 5
+}`
+
+func TestEffectiveStreamRetention(t *testing.T) {
+	definitions := []map[string]interface{}{
+		{"max-age": "7D"},
+		{"max-age": "1D", "max-length-bytes": float64(1000)},
+		{},
+	}
+	arguments := map[string]interface{}{"x-max-length-bytes": float64(2000)}
+
+	if v := effectiveStreamRetention("7D", "max-age", nil, definitions[:1]); v != "7D" {
+		t.Errorf("Expected the max-age of the policy, got %v", v)
+	}
+	if v := effectiveStreamRetention("7D", "max-age", nil, definitions); v != "1D" {
+		t.Errorf("Expected the max-age of the policy shadowing it, got %v", v)
+	}
+	if v := effectiveStreamRetention(float64(1000), "max-length-bytes", nil, definitions); v != nil {
+		t.Errorf("Expected no max-length-bytes for a stream without policy, got %v", v)
+	}
+	if v := effectiveStreamRetention(float64(2000), "max-length-bytes", arguments, definitions[2:]); v != float64(2000) {
+		t.Errorf("Expected the max-length-bytes of the arguments, got %v", v)
+	}
+	if v := effectiveStreamRetention(float64(2000), "max-length-bytes", arguments, definitions[1:2]); v != float64(1000) {
+		t.Errorf("Expected the max-length-bytes of the policy over the arguments, got %v", v)
+	}
+}
+
+func TestApplyStreamRetentionPolicy(t *testing.T) {
+	var requests []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		w.Header().Set("Content-Type", "application/json")
+		switch path := r.URL.EscapedPath(); {
+		case r.Method == http.MethodGet && path == "/api/policies/test/other":
+			fmt.Fprint(w, `{"vhost": "test", "name": "other", "pattern": "^test$", "apply-to": "queues", "priority": 0, "definition": {"max-age": "1D"}}`)
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": "Object Not Found", "reason": "Not Found"}`)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer api.Close()
+
+	rmqc, err := rabbithole.NewClient(api.URL, "guest", "guest")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceStream().Schema, map[string]interface{}{
+		"name":    "test",
+		"vhost":   "test",
+		"max_age": "7D",
+	})
+
+	if err := applyStreamRetentionPolicy(d, rmqc, "test", "test-stream-retention", "^test$"); err != nil {
+		t.Fatal(err)
+	}
+	if v := d.Get("retention_policy"); v != "test-stream-retention" {
+		t.Errorf("Expected the policy to be managed by the resource, got %q", v)
+	}
+
+	requests = nil
+	err = applyStreamRetentionPolicy(d, rmqc, "test", "other", "^test$")
+	if err == nil || !strings.Contains(err.Error(), "is not managed by this resource") {
+		t.Fatalf("Expected an error for a policy not managed by the resource, got %v", err)
+	}
+	if len(requests) != 1 || requests[0] != "GET /api/policies/test/other" {
+		t.Errorf("Expected the policies to be left untouched, got %v", requests)
+	}
+	if v := d.Get("retention_policy"); v != "test-stream-retention" {
+		t.Errorf("Expected the previous policy to stay managed by the resource, got %q", v)
+	}
+}
@@ -0,0 +1,436 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+)
+
+// A super stream is an exchange marked with the `x-super-stream` argument,
+// bound to one stream per partition. The order of the partitions is held by
+// the `x-stream-partition-order` argument of the bindings. The members are
+// declared the same way as by `rabbitmq-streams add_super_stream`.
+
+func resourceSuperStream() *schema.Resource {
+	return &schema.Resource{
+		Create:        CreateSuperStream,
+		Read:          ReadSuperStream,
+		Update:        UpdateSuperStream,
+		Delete:        DeleteSuperStream,
+		CustomizeDiff: customizeSuperStreamDiff,
+		Description:   "The `rabbitmq_super_stream` resource creates and manages a super stream, a partitioned stream made of an exchange, one stream per partition and the bindings between them, in a RabbitMQ server.",
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: withStreamRetentionSchema("<name>-super-stream-retention", map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the super stream.",
+			},
+
+			"vhost": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "/",
+				ForceNew:    true,
+				Description: "The vhost to create the resource in.",
+			},
+
+			"partitions": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"partitions", "binding_keys"},
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The number of partitions of the super stream. The partitions are named `<name>-<n>` and bound with the routing key `<n>`, `n` starting at 0. Increasing the number of partitions adds partitions, decreasing it recreates the super stream.",
+			},
+
+			"binding_keys": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+				ExactlyOneOf: []string{"partitions", "binding_keys"},
+				Description:  "The binding keys of the partitions of the super stream, in partition order. The partitions are named `<name>-<binding key>`. Appending binding keys adds partitions, any other change recreates the super stream.",
+			},
+
+			"initial_cluster_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The number of nodes each partition is initially replicated to. Sets the `x-initial-cluster-size` argument.",
+			},
+
+			"leader_locator": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"client-local", "balanced"}, false),
+				Description:  "The rule used to place the leader of each partition. Valid values are `client-local` and `balanced`. Sets the `x-queue-leader-locator` argument.",
+			},
+
+			"streams": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the partition streams, in partition order.",
+			},
+
+			"retention_policy": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the policy holding the retention settings of the partitions. As only one policy applies to a stream, other policies matching the partitions with a lower priority are ignored while retention settings are set.",
+			},
+		}),
+	}
+}
+
+func CreateSuperStream(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	name := d.Get("name").(string)
+	vhost := d.Get("vhost").(string)
+
+	exchangeSettings := map[string]interface{}{
+		// The broker declares the exchange of a super stream as a direct exchange.
+		"type":    "direct",
+		"durable": true,
+		"arguments": map[string]interface{}{
+			"x-super-stream": true,
+		},
+	}
+	if err := declareExchange(rmqc, vhost, name, exchangeSettings); err != nil {
+		return err
+	}
+
+	id := fmt.Sprintf("%s@%s", name, vhost)
+	d.SetId(id)
+
+	bindingKeys := superStreamBindingKeys(d)
+	if err := addSuperStreamPartitions(d, rmqc, vhost, name, bindingKeys, 0); err != nil {
+		return err
+	}
+
+	if policyName := streamRetentionPolicyName(d, superStreamRetentionPolicyName(name)); policyName != "" {
+		if err := applyStreamRetentionPolicy(d, rmqc, vhost, policyName, superStreamRetentionPattern(name, bindingKeys)); err != nil {
+			return err
+		}
+	}
+
+	return ReadSuperStream(d, meta)
+}
+
+func ReadSuperStream(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	name, vhost, err := parseResourceId(d)
+	if err != nil {
+		return err
+	}
+
+	exchange, err := rmqc.GetExchange(vhost, name)
+	if err != nil {
+		return checkDeleted(d, err)
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: Super stream exchange retrieved for %s: %#v", d.Id(), exchange)
+
+	if v, ok := exchange.Arguments["x-super-stream"].(bool); !ok || !v {
+		return fmt.Errorf("Exchange %s is not the exchange of a super stream", d.Id())
+	}
+
+	partitions, err := listSuperStreamPartitions(rmqc, vhost, name)
+	if err != nil {
+		return err
+	}
+
+	d.Set("name", exchange.Name)
+	d.Set("vhost", exchange.Vhost)
+
+	bindingKeys := make([]string, len(partitions))
+	streams := make([]string, len(partitions))
+	for i, partition := range partitions {
+		bindingKeys[i] = partition.RoutingKey
+		streams[i] = partition.Destination
+	}
+	d.Set("partitions", len(partitions))
+	d.Set("binding_keys", bindingKeys)
+	d.Set("streams", streams)
+
+	// All partitions are declared with the same arguments,
+	// but their effective policies may differ.
+	var arguments map[string]interface{}
+	var effectiveDefinitions []map[string]interface{}
+	for _, stream := range streams {
+		var partition struct {
+			policyObjectInfo
+			Arguments map[string]interface{} `json:"arguments"`
+		}
+		path := fmt.Sprintf("queues/%s/%s", url.PathEscape(vhost), url.PathEscape(stream))
+		if _, err := managementRequest(rmqc, http.MethodGet, path, nil, &partition); err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		if arguments == nil {
+			arguments = partition.Arguments
+		}
+		effectiveDefinitions = append(effectiveDefinitions, partition.EffectivePolicyDefinition)
+	}
+
+	if v, ok := arguments["x-initial-cluster-size"].(float64); ok {
+		d.Set("initial_cluster_size", int(v))
+	} else {
+		d.Set("initial_cluster_size", 0)
+	}
+	if v, ok := arguments["x-queue-leader-locator"].(string); ok {
+		d.Set("leader_locator", v)
+	} else {
+		d.Set("leader_locator", "")
+	}
+
+	return readStreamRetention(d, rmqc, vhost, superStreamRetentionPolicyName(name), arguments, effectiveDefinitions)
+}
+
+func UpdateSuperStream(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	name, vhost, err := parseResourceId(d)
+	if err != nil {
+		return err
+	}
+
+	bindingKeys := superStreamBindingKeys(d)
+	partitionsChanged := d.HasChanges("partitions", "binding_keys")
+
+	if partitionsChanged {
+		// The plan only keeps the super stream when partitions are appended.
+		old, _ := d.GetChange("binding_keys")
+		if err := addSuperStreamPartitions(d, rmqc, vhost, name, bindingKeys, len(old.([]interface{}))); err != nil {
+			return err
+		}
+	}
+
+	if policyName := streamRetentionPolicyName(d, superStreamRetentionPolicyName(name)); policyName != "" && (partitionsChanged || d.HasChanges(streamRetentionPolicyAttributes...)) {
+		if err := applyStreamRetentionPolicy(d, rmqc, vhost, policyName, superStreamRetentionPattern(name, bindingKeys)); err != nil {
+			return err
+		}
+	}
+
+	return ReadSuperStream(d, meta)
+}
+
+func DeleteSuperStream(d *schema.ResourceData, meta interface{}) error {
+	rmqc := meta.(*providerMeta).Client
+
+	name, vhost, err := parseResourceId(d)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] RabbitMQ: Attempting to delete super stream %s", d.Id())
+
+	streams := make(map[string]bool)
+	for _, v := range d.Get("streams").([]interface{}) {
+		streams[v.(string)] = true
+	}
+
+	// Partitions added outside of Terraform are removed along with the super stream.
+	partitions, err := listSuperStreamPartitions(rmqc, vhost, name)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	for _, partition := range partitions {
+		streams[partition.Destination] = true
+	}
+
+	resp, err := rmqc.DeleteExchange(vhost, name)
+	log.Printf("[DEBUG] RabbitMQ: Exchange delete response: %#v", resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 && resp.StatusCode != 404 {
+		return fmt.Errorf("Error deleting RabbitMQ super stream exchange: %s", resp.Status)
+	}
+
+	for stream := range streams {
+		resp, err := rmqc.DeleteQueue(vhost, stream)
+		log.Printf("[DEBUG] RabbitMQ: Stream delete response: %#v", resp)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode >= 400 && resp.StatusCode != 404 {
+			return fmt.Errorf("Error deleting RabbitMQ stream %s: %s", stream, resp.Status)
+		}
+	}
+
+	// Only the retention policy managed by the resource is deleted.
+	if policyName := d.Get("retention_policy").(string); policyName != "" {
+		return deleteStreamRetentionPolicy(rmqc, vhost, policyName)
+	}
+
+	return nil
+}
+
+// customizeSuperStreamDiff keeps the super stream when partitions are only
+// appended to it, and recreates it otherwise or when retention settings held
+// by arguments change.
+func customizeSuperStreamDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() == "" {
+		return nil
+	}
+
+	if err := customizeStreamRetentionDiff(ctx, diff, meta); err != nil {
+		return err
+	}
+
+	if diff.HasChange("partitions") {
+		old, new := diff.GetChange("partitions")
+		if new.(int) < old.(int) {
+			if err := diff.ForceNew("partitions"); err != nil {
+				return err
+			}
+		}
+		if err := diff.SetNewComputed("binding_keys"); err != nil {
+			return err
+		}
+		return diff.SetNewComputed("streams")
+	}
+
+	if diff.HasChange("binding_keys") && diff.NewValueKnown("binding_keys") {
+		old, new := diff.GetChange("binding_keys")
+		oldKeys := old.([]interface{})
+		newKeys := new.([]interface{})
+		appended := len(newKeys) >= len(oldKeys)
+		for i := 0; appended && i < len(oldKeys); i++ {
+			appended = oldKeys[i] == newKeys[i]
+		}
+		if !appended {
+			if err := diff.ForceNew("binding_keys"); err != nil {
+				return err
+			}
+		}
+		if err := diff.SetNew("partitions", len(newKeys)); err != nil {
+			return err
+		}
+		return diff.SetNewComputed("streams")
+	}
+
+	return nil
+}
+
+// superStreamBindingKeys returns the binding keys of the partitions set in d,
+// in partition order. Binding keys are generated from the number of partitions
+// when they are not set.
+func superStreamBindingKeys(d *schema.ResourceData) []string {
+	var bindingKeys []string
+	for _, key := range d.Get("binding_keys").([]interface{}) {
+		bindingKeys = append(bindingKeys, key.(string))
+	}
+	if len(bindingKeys) > 0 {
+		return bindingKeys
+	}
+
+	bindingKeys = make([]string, d.Get("partitions").(int))
+	for i := range bindingKeys {
+		bindingKeys[i] = strconv.Itoa(i)
+	}
+	return bindingKeys
+}
+
+func superStreamPartitionName(name string, bindingKey string) string {
+	return fmt.Sprintf("%s-%s", name, bindingKey)
+}
+
+func superStreamRetentionPolicyName(name string) string {
+	return fmt.Sprintf("%s-super-stream-retention", name)
+}
+
+// addSuperStreamPartitions declares the partitions of the super stream
+// starting from the partition at index from.
+func addSuperStreamPartitions(d *schema.ResourceData, rmqc *rabbithole.Client, vhost string, name string, bindingKeys []string, from int) error {
+	arguments := map[string]interface{}{}
+	if v, ok := d.GetOk("initial_cluster_size"); ok {
+		arguments["x-initial-cluster-size"] = v.(int)
+	}
+	if v, ok := d.GetOk("leader_locator"); ok {
+		arguments["x-queue-leader-locator"] = v.(string)
+	}
+	addStreamRetentionArguments(d, arguments)
+
+	for i := from; i < len(bindingKeys); i++ {
+		stream := superStreamPartitionName(name, bindingKeys[i])
+		if err := declareStream(rmqc, vhost, stream, arguments); err != nil {
+			return err
+		}
+
+		bindingInfo := rabbithole.BindingInfo{
+			Source:          name,
+			Destination:     stream,
+			DestinationType: "queue",
+			RoutingKey:      bindingKeys[i],
+			Arguments: map[string]interface{}{
+				"x-stream-partition-order": i,
+			},
+		}
+		if _, err := declareBinding(rmqc, vhost, bindingInfo); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// superStreamRetentionPattern returns the pattern of the retention policy,
+// matching the partitions of the super stream.
+func superStreamRetentionPattern(name string, bindingKeys []string) string {
+	streams := make([]string, len(bindingKeys))
+	for i, key := range bindingKeys {
+		streams[i] = regexp.QuoteMeta(superStreamPartitionName(name, key))
+	}
+	return fmt.Sprintf("^(%s)$", strings.Join(streams, "|"))
+}
+
+// listSuperStreamPartitions returns the bindings from the super stream
+// exchange to its partitions, in partition order.
+func listSuperStreamPartitions(rmqc *rabbithole.Client, vhost string, name string) ([]rabbithole.BindingInfo, error) {
+	bindings, err := rmqc.ListExchangeBindingsWithSource(vhost, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var partitions []rabbithole.BindingInfo
+	for _, binding := range bindings {
+		if _, ok := binding.Arguments["x-stream-partition-order"].(float64); ok && binding.DestinationType == "queue" {
+			partitions = append(partitions, binding)
+		}
+	}
+
+	sort.SliceStable(partitions, func(i, j int) bool {
+		return partitions[i].Arguments["x-stream-partition-order"].(float64) < partitions[j].Arguments["x-stream-partition-order"].(float64)
+	})
+
+	return partitions, nil
+}
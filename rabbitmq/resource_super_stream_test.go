@@ -0,0 +1,313 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccSuperStream_basic(t *testing.T) {
+	var exchangeInfo rabbithole.ExchangeInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccSuperStreamCheckDestroy(&exchangeInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSuperStreamConfig_partitions(2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSuperStreamCheck("rabbitmq_super_stream.test", &exchangeInfo, []string{"0", "1"}),
+					resource.TestCheckResourceAttr("rabbitmq_super_stream.test", "streams.#", "2"),
+					resource.TestCheckResourceAttr("rabbitmq_super_stream.test", "streams.1", "invoices-1"),
+					resource.TestCheckResourceAttr("rabbitmq_super_stream.test", "retention_policy", "invoices-super-stream-retention"),
+				),
+			},
+			{
+				// Partitions are added in place.
+				Config: testAccSuperStreamConfig_partitions(3),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSuperStreamCheck("rabbitmq_super_stream.test", &exchangeInfo, []string{"0", "1", "2"}),
+					resource.TestCheckResourceAttr("rabbitmq_super_stream.test", "streams.#", "3"),
+					resource.TestCheckResourceAttr("rabbitmq_super_stream.test", "binding_keys.2", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSuperStream_bindingKeys(t *testing.T) {
+	var exchangeInfo rabbithole.ExchangeInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccSuperStreamCheckDestroy(&exchangeInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSuperStreamConfig_bindingKeys(`["amer", "emea"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSuperStreamCheck("rabbitmq_super_stream.test", &exchangeInfo, []string{"amer", "emea"}),
+					resource.TestCheckResourceAttr("rabbitmq_super_stream.test", "partitions", "2"),
+					resource.TestCheckResourceAttr("rabbitmq_super_stream.test", "streams.0", "invoices-amer"),
+				),
+			},
+			{
+				Config: testAccSuperStreamConfig_bindingKeys(`["amer", "emea", "apac"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSuperStreamCheck("rabbitmq_super_stream.test", &exchangeInfo, []string{"amer", "emea", "apac"}),
+					resource.TestCheckResourceAttr("rabbitmq_super_stream.test", "partitions", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSuperStream_retentionArguments(t *testing.T) {
+	var exchangeInfo rabbithole.ExchangeInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccSuperStreamCheckDestroy(&exchangeInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSuperStreamConfig_retentionArguments(2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSuperStreamCheck("rabbitmq_super_stream.test", &exchangeInfo, []string{"0", "1"}),
+					testAccSuperStreamCheckPartitionArgument(&exchangeInfo, "invoices-1", "x-max-age", "7D"),
+					resource.TestCheckResourceAttr("rabbitmq_super_stream.test", "max_age", "7D"),
+					resource.TestCheckResourceAttr("rabbitmq_super_stream.test", "retention_policy", ""),
+				),
+			},
+			{
+				// New partitions are declared with the same arguments.
+				Config: testAccSuperStreamConfig_retentionArguments(3),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSuperStreamCheck("rabbitmq_super_stream.test", &exchangeInfo, []string{"0", "1", "2"}),
+					testAccSuperStreamCheckPartitionArgument(&exchangeInfo, "invoices-2", "x-max-age", "7D"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSuperStream_shadowedRetention(t *testing.T) {
+	var exchangeInfo rabbithole.ExchangeInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccSuperStreamCheckDestroy(&exchangeInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSuperStreamConfig_partitions(2),
+				Check:  testAccSuperStreamCheck("rabbitmq_super_stream.test", &exchangeInfo, []string{"0", "1"}),
+			},
+			{
+				// A policy with a higher priority shadows the retention of one partition.
+				PreConfig: func() {
+					rmqc := testAccProvider.Meta().(*providerMeta).Client
+					policy := rabbithole.Policy{
+						Pattern:    "^invoices-1$",
+						ApplyTo:    "queues",
+						Priority:   10,
+						Definition: rabbithole.PolicyDefinition{"max-age": "1D"},
+					}
+					if _, err := rmqc.PutPolicy(exchangeInfo.Vhost, "override", policy); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:   testAccSuperStreamConfig_partitions(2),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccSuperStreamConfig_partitions(2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("rabbitmq_super_stream.test", "max_age", "7D"),
+					resource.TestCheckResourceAttr("rabbitmq_super_stream.test", "effective_max_age", "1D"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSuperStream_cappedRetention(t *testing.T) {
+	var exchangeInfo rabbithole.ExchangeInfo
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccSuperStreamCheckDestroy(&exchangeInfo),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSuperStreamConfig_maxLengthBytes(2, 20000000),
+				Check:  testAccSuperStreamCheck("rabbitmq_super_stream.test", &exchangeInfo, []string{"0", "1"}),
+			},
+			{
+				// An operator policy caps the max-length-bytes of the partitions.
+				PreConfig: testAccStreamPutOperatorPolicy(t, "test", "^invoices-", 1000000),
+				Config:    testAccSuperStreamConfig_maxLengthBytes(2, 20000000),
+				PlanOnly:  true,
+			},
+			{
+				Config: testAccSuperStreamConfig_maxLengthBytes(2, 20000000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("rabbitmq_super_stream.test", "max_length_bytes", "20000000"),
+					resource.TestCheckResourceAttr("rabbitmq_super_stream.test", "effective_max_length_bytes", "1000000"),
+				),
+			},
+		},
+	})
+}
+
+func testAccSuperStreamCheckPartitionArgument(exchangeInfo *rabbithole.ExchangeInfo, partition string, key string, value interface{}) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+
+		stream, err := rmqc.GetQueue(exchangeInfo.Vhost, partition)
+		if err != nil {
+			return fmt.Errorf("Error retrieving partition %s: %s", partition, err)
+		}
+
+		if v := stream.Arguments[key]; v != value {
+			return fmt.Errorf("Expected argument %s of partition %s to be %v, got %v", key, partition, value, v)
+		}
+		return nil
+	}
+}
+
+func testAccSuperStreamCheck(rn string, exchangeInfo *rabbithole.ExchangeInfo, bindingKeys []string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("super stream id not set")
+		}
+
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+		superStreamParts := strings.Split(rs.Primary.ID, "@")
+
+		exchange, err := rmqc.GetExchange(superStreamParts[1], superStreamParts[0])
+		if err != nil {
+			return fmt.Errorf("Error retrieving super stream exchange: %s", err)
+		}
+
+		if v, ok := exchange.Arguments["x-super-stream"].(bool); !ok || !v {
+			return fmt.Errorf("Exchange %s is not marked as a super stream: %#v", rs.Primary.ID, exchange.Arguments)
+		}
+
+		partitions, err := listSuperStreamPartitions(rmqc, exchange.Vhost, exchange.Name)
+		if err != nil {
+			return fmt.Errorf("Error retrieving super stream bindings: %s", err)
+		}
+
+		if len(partitions) != len(bindingKeys) {
+			return fmt.Errorf("Super stream %s has %d partitions, expected %d", rs.Primary.ID, len(partitions), len(bindingKeys))
+		}
+
+		for i, partition := range partitions {
+			if partition.RoutingKey != bindingKeys[i] {
+				return fmt.Errorf("Partition %d of super stream %s has binding key %s, expected %s", i, rs.Primary.ID, partition.RoutingKey, bindingKeys[i])
+			}
+
+			stream, err := rmqc.GetQueue(exchange.Vhost, partition.Destination)
+			if err != nil {
+				return fmt.Errorf("Error retrieving partition %s: %s", partition.Destination, err)
+			}
+			if stream.Type != "stream" {
+				return fmt.Errorf("Partition %s is a %s queue, not a stream", partition.Destination, stream.Type)
+			}
+		}
+
+		*exchangeInfo = rabbithole.ExchangeInfo{Name: exchange.Name, Vhost: exchange.Vhost}
+		return nil
+	}
+}
+
+func testAccSuperStreamCheckDestroy(exchangeInfo *rabbithole.ExchangeInfo) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
+
+		if _, err := rmqc.GetExchange(exchangeInfo.Vhost, exchangeInfo.Name); err == nil {
+			return fmt.Errorf("Super stream exchange %s@%s still exist", exchangeInfo.Name, exchangeInfo.Vhost)
+		}
+
+		queues, err := rmqc.ListQueuesIn(exchangeInfo.Vhost)
+		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "not found") {
+			return fmt.Errorf("Error retrieving queues: %s", err)
+		}
+
+		for _, queue := range queues {
+			if strings.HasPrefix(queue.Name, exchangeInfo.Name+"-") {
+				return fmt.Errorf("Partition %s@%s still exist", queue.Name, queue.Vhost)
+			}
+		}
+
+		if _, err := rmqc.GetPolicy(exchangeInfo.Vhost, superStreamRetentionPolicyName(exchangeInfo.Name)); err == nil {
+			return fmt.Errorf("Retention policy of super stream %s@%s still exist", exchangeInfo.Name, exchangeInfo.Vhost)
+		}
+
+		return nil
+	}
+}
+
+const testAccSuperStreamConfig_vhost = `
+resource "rabbitmq_vhost" "test" {
+	name = "test"
+}
+
+resource "rabbitmq_permissions" "guest" {
+	user = "guest"
+	vhost = "${rabbitmq_vhost.test.name}"
+	permissions {
+		configure = ".*"
+		write = ".*"
+		read = ".*"
+	}
+}
+`
+
+func testAccSuperStreamConfig_partitions(partitions int) string {
+	return testAccSuperStreamConfig_vhost + fmt.Sprintf(`
+resource "rabbitmq_super_stream" "test" {
+	name = "invoices"
+	vhost = "${rabbitmq_permissions.guest.vhost}"
+	partitions = %d
+	initial_cluster_size = 1
+	max_age = "7D"
+}`, partitions)
+}
+
+func testAccSuperStreamConfig_maxLengthBytes(partitions int, maxLengthBytes int) string {
+	return testAccSuperStreamConfig_vhost + fmt.Sprintf(`
+resource "rabbitmq_super_stream" "test" {
+	name = "invoices"
+	vhost = "${rabbitmq_permissions.guest.vhost}"
+	partitions = %d
+	max_length_bytes = %d
+}`, partitions, maxLengthBytes)
+}
+
+func testAccSuperStreamConfig_bindingKeys(bindingKeys string) string {
+	return testAccSuperStreamConfig_vhost + fmt.Sprintf(`
+resource "rabbitmq_super_stream" "test" {
+	name = "invoices"
+	vhost = "${rabbitmq_permissions.guest.vhost}"
+	binding_keys = %s
+}`, bindingKeys)
+}
+
+func testAccSuperStreamConfig_retentionArguments(partitions int) string {
+	return testAccSuperStreamConfig_vhost + fmt.Sprintf(`
+resource "rabbitmq_super_stream" "test" {
+	name = "invoices"
+	vhost = "${rabbitmq_permissions.guest.vhost}"
+	partitions = %d
+	retention_strategy = "arguments"
+	max_age = "7D"
+}`, partitions)
+}
@@ -69,7 +69,7 @@ func resourceTopicPermissions() *schema.Resource {
 
 // CreateTopicPermissions for given exchanges
 func CreateTopicPermissions(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	user := d.Get("user").(string)
 	vhost := d.Get("vhost").(string)
@@ -95,7 +95,7 @@ func CreateTopicPermissions(d *schema.ResourceData, meta interface{}) error {
 
 // ReadTopicPermissions for the given ID
 func ReadTopicPermissions(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	user, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -127,7 +127,7 @@ func ReadTopicPermissions(d *schema.ResourceData, meta interface{}) error {
 
 // UpdateTopicPermissions for given ID
 func UpdateTopicPermissions(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	user, vhost, err := parseResourceId(d)
 	if err != nil {
@@ -158,7 +158,7 @@ func UpdateTopicPermissions(d *schema.ResourceData, meta interface{}) error {
 
 // DeleteTopicPermissions for given ID
 func DeleteTopicPermissions(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	user, vhost, err := parseResourceId(d)
 	if err != nil {
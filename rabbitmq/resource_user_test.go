@@ -155,7 +155,7 @@ func testAccUserCheck(rn string, name *string) resource.TestCheckFunc {
 			return fmt.Errorf("user id not set")
 		}
 
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		users, err := rmqc.ListUsers()
 		if err != nil {
 			return fmt.Errorf("Error retrieving users: %s", err)
@@ -189,7 +189,7 @@ func testAccUserConnect(username, password string) resource.TestCheckFunc {
 
 func testAccUserCheckTagCount(name *string, tagCount int) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		user, err := rmqc.GetUser(*name)
 		if err != nil {
 			return fmt.Errorf("Error retrieving user: %s", err)
@@ -212,7 +212,7 @@ func testAccUserCheckTagCount(name *string, tagCount int) resource.TestCheckFunc
 
 func testAccUserCheckDestroy(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		rmqc := testAccProvider.Meta().(*rabbithole.Client)
+		rmqc := testAccProvider.Meta().(*providerMeta).Client
 		users, err := rmqc.ListUsers()
 		if err != nil {
 			return fmt.Errorf("Error retrieving users: %s", err)
@@ -31,7 +31,7 @@ func resourceVhost() *schema.Resource {
 }
 
 func CreateVhost(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	vhost := d.Get("name").(string)
 
@@ -49,7 +49,7 @@ func CreateVhost(d *schema.ResourceData, meta interface{}) error {
 }
 
 func ReadVhost(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	vhost, err := rmqc.GetVhost(d.Id())
 	if err != nil {
@@ -64,7 +64,7 @@ func ReadVhost(d *schema.ResourceData, meta interface{}) error {
 }
 
 func DeleteVhost(d *schema.ResourceData, meta interface{}) error {
-	rmqc := meta.(*rabbithole.Client)
+	rmqc := meta.(*providerMeta).Client
 
 	log.Printf("[DEBUG] RabbitMQ: Attempting to delete vhost %s", d.Id())
 
@@ -1,8 +1,10 @@
 package rabbitmq
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -20,6 +22,59 @@ func checkDeleted(d *schema.ResourceData, err error) error {
 	return err
 }
 
+// isNotFound reports whether err is a 404 response of the management API.
+func isNotFound(err error) bool {
+	var errorResponse rabbithole.ErrorResponse
+	return errors.As(err, &errorResponse) && errorResponse.StatusCode == 404
+}
+
+// flattenArguments returns arguments as read by the data sources, both as
+// a map of strings, with non-string values encoded as JSON, and as a JSON
+// document which keeps the original value types.
+func flattenArguments(serverArguments map[string]interface{}) (map[string]interface{}, string, error) {
+	arguments := make(map[string]interface{}, len(serverArguments))
+	for key, value := range serverArguments {
+		if v, ok := value.(string); ok {
+			arguments[key] = v
+			continue
+		}
+
+		bytes, err := json.Marshal(value)
+		if err != nil {
+			return nil, "", err
+		}
+		arguments[key] = string(bytes)
+	}
+
+	argumentsJson := "{}"
+	if len(serverArguments) > 0 {
+		bytes, err := json.Marshal(serverArguments)
+		if err != nil {
+			return nil, "", err
+		}
+		argumentsJson = string(bytes)
+	}
+
+	return arguments, argumentsJson, nil
+}
+
+// configuredArguments returns the arguments currently held by the
+// `arguments` or `arguments_json` settings of a queue or an exchange.
+func configuredArguments(d *schema.ResourceData) map[string]interface{} {
+	if v, ok := d.Get("settings.0.arguments_json").(string); ok && v != "" {
+		var arguments map[string]interface{}
+		if err := json.Unmarshal([]byte(v), &arguments); err == nil {
+			return arguments
+		}
+	}
+
+	if v, ok := d.Get("settings.0.arguments").(map[string]interface{}); ok {
+		return v
+	}
+
+	return nil
+}
+
 // Because slashes are used to separate different components when constructing binding IDs,
 // we need a way to ensure any components that include slashes can survive the round trip.
 // Percent-encoding is a straightforward way of doing so.
@@ -51,3 +106,73 @@ func parseId(resourceId string) (name, vhost string, err error) {
 	vhost = parts[1]
 	return
 }
+
+// bindingId returns the ID of a binding: its vhost, source, destination,
+// destination type and properties key, each percent-encoded, separated by slashes.
+func bindingId(vhost string, binding rabbithole.BindingInfo) string {
+	components := []string{vhost, binding.Source, binding.Destination, binding.DestinationType, binding.PropertiesKey}
+	for i, component := range components {
+		components[i] = percentEncodeSlashes(component)
+	}
+	return strings.Join(components, "/")
+}
+
+// parseBindingId returns the vhost and the binding identified by a binding ID.
+func parseBindingId(id string) (vhost string, binding rabbithole.BindingInfo, err error) {
+	components := strings.Split(id, "/")
+	if len(components) != 5 {
+		return "", rabbithole.BindingInfo{}, fmt.Errorf("Unable to determine binding ID from %s, expected vhost/source/destination/destination_type/properties_key", id)
+	}
+
+	for i, component := range components {
+		components[i] = percentDecodeSlashes(component)
+	}
+
+	vhost = components[0]
+	binding = rabbithole.BindingInfo{
+		Vhost:           vhost,
+		Source:          components[1],
+		Destination:     components[2],
+		DestinationType: components[3],
+		PropertiesKey:   components[4],
+	}
+
+	return vhost, binding, nil
+}
+
+// versionAtLeast returns whether a RabbitMQ version, e.g. 3.12.4 or
+// 4.0.0-rc.1, is at least major.minor.
+func versionAtLeast(version string, major int, minor int) bool {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return false
+	}
+
+	v, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	if v != major {
+		return v > major
+	}
+
+	// The minor version may be followed by a pre-release suffix, e.g. 4.0-rc.1.
+	minorPart := parts[1]
+	if i := strings.IndexFunc(minorPart, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		minorPart = minorPart[:i]
+	}
+	v, err = strconv.Atoi(minorPart)
+	if err != nil {
+		return false
+	}
+	return v >= minor
+}
+
+// serverVersion returns the version of the RabbitMQ server.
+func serverVersion(rmqc *rabbithole.Client) (string, error) {
+	overview, err := rmqc.Overview()
+	if err != nil {
+		return "", fmt.Errorf("Error retrieving the RabbitMQ version: %w", err)
+	}
+	return overview.RabbitMQVersion, nil
+}
@@ -1,6 +1,11 @@
 package rabbitmq
 
-import "testing"
+import (
+	"testing"
+	"testing/quick"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+)
 
 func TestParseId(t *testing.T) {
 	var badInputs = []string{
@@ -34,3 +39,94 @@ func TestParseId(t *testing.T) {
 		}
 	}
 }
+
+func TestBindingId(t *testing.T) {
+	var inputs = []struct {
+		vhost   string
+		binding rabbithole.BindingInfo
+		id      string
+	}{
+		{"/", rabbithole.BindingInfo{Source: "test", Destination: "test", DestinationType: "queue", PropertiesKey: "~"}, "%2F/test/test/queue/~"},
+		{"test", rabbithole.BindingInfo{Source: "a/b", Destination: "c%2Fd", DestinationType: "exchange", PropertiesKey: "%2Fkey~"}, "test/a%2Fb/c%252Fd/exchange/%252Fkey~"},
+		{"/virtual//host///", rabbithole.BindingInfo{Source: "événements/🐇", Destination: "файлы", DestinationType: "queue", PropertiesKey: "routing.key~abc"}, "%2Fvirtual%2F%2Fhost%2F%2F%2F/événements%2F🐇/файлы/queue/routing.key~abc"},
+	}
+
+	for _, test := range inputs {
+		id := bindingId(test.vhost, test.binding)
+		if id != test.id {
+			t.Errorf("bindingId returned %s, expected %s.", id, test.id)
+		}
+
+		vhost, binding, err := parseBindingId(id)
+		if err != nil || vhost != test.vhost || binding.Source != test.binding.Source || binding.Destination != test.binding.Destination ||
+			binding.DestinationType != test.binding.DestinationType || binding.PropertiesKey != test.binding.PropertiesKey {
+			t.Errorf("parseBindingId failed for: %s.", id)
+		}
+	}
+
+	var badInputs = []string{
+		"",
+		"test/source/destination/queue",
+		"test/source/destination/queue/key/extra",
+	}
+
+	for _, input := range badInputs {
+		if _, _, err := parseBindingId(input); err == nil {
+			t.Errorf("parseBindingId should have failed for: %s.", input)
+		}
+	}
+}
+
+func TestBindingId_roundTrip(t *testing.T) {
+	roundTrip := func(vhost, source, destination, destinationType, propertiesKey string) bool {
+		binding := rabbithole.BindingInfo{
+			Source:          source,
+			Destination:     destination,
+			DestinationType: destinationType,
+			PropertiesKey:   propertiesKey,
+		}
+
+		parsedVhost, parsed, err := parseBindingId(bindingId(vhost, binding))
+		return err == nil && parsedVhost == vhost && parsed.Vhost == vhost && parsed.Source == source &&
+			parsed.Destination == destination && parsed.DestinationType == destinationType && parsed.PropertiesKey == propertiesKey
+	}
+
+	// Random strings are made of arbitrary unicode code points.
+	if err := quick.Check(roundTrip, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+
+	// Random strings rarely contain the characters used by the encoding.
+	separators := func(s string) string { return "/%" + s + "%2F/%25" }
+	if err := quick.Check(func(vhost, source, destination, destinationType, propertiesKey string) bool {
+		return roundTrip(separators(vhost), separators(source), separators(destination), separators(destinationType), separators(propertiesKey))
+	}, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	var cases = []struct {
+		version  string
+		major    int
+		minor    int
+		expected bool
+	}{
+		{"3.12.0", 3, 12, true},
+		{"3.13.7", 3, 12, true},
+		{"3.9.29", 3, 12, false},
+		{"3.7.0", 3, 7, true},
+		{"4.0.0-rc.1", 4, 0, true},
+		{"4.1-beta.2", 4, 1, true},
+		{"3.12.0", 4, 0, false},
+		{"4.0.5", 3, 12, true},
+		{"", 3, 7, false},
+		{"unknown", 3, 7, false},
+	}
+
+	for _, c := range cases {
+		if actual := versionAtLeast(c.version, c.major, c.minor); actual != c.expected {
+			t.Errorf("versionAtLeast(%q, %d, %d) = %v, expected %v", c.version, c.major, c.minor, actual, c.expected)
+		}
+	}
+}